@@ -0,0 +1,99 @@
+package tenkft
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AdaptivePaging makes GetAll* methods shrink their page size while pages are slow or
+// fail with a 5xx status, and grow it back once pages are healthy again, so that big
+// dumps keep going while the API is degraded. Pages failing with a 5xx are retried at
+// the smaller size per Client.PageRetries. Zero fields take their defaults.
+type AdaptivePaging struct {
+	// SlowPage is the time after which a page counts as slow, 10s by default.
+	SlowPage time.Duration
+	// MinPerPage is the smallest page size used, 10 by default.
+	MinPerPage int
+	// HealthyPages is the number of healthy pages in a row after which the page size
+	// grows back, 3 by default.
+	HealthyPages int
+}
+
+// WithAdaptivePaging makes GetAll* methods adapt their page size, see AdaptivePaging.
+func WithAdaptivePaging(a AdaptivePaging) ClientOption {
+	return func(c *Client) {
+		if a.SlowPage == 0 {
+			a.SlowPage = 10 * time.Second
+		}
+		if a.MinPerPage == 0 {
+			a.MinPerPage = 10
+		}
+		if a.HealthyPages == 0 {
+			a.HealthyPages = 3
+		}
+		c.adaptive = &a
+	}
+}
+
+// pageSizer picks the page size of a pagination under AdaptivePaging. The API numbers
+// pages by size, so sizes are picked among the divisors of the number of items already
+// paginated, for the next page to start right after them.
+type pageSizer struct {
+	AdaptivePaging
+	max, size int
+	// start is the number of items before the first page requested.
+	start   int
+	healthy int
+}
+
+// newPageSizer starts at size perPage, query holding the first page requested.
+func newPageSizer(a AdaptivePaging, perPage int, query map[string]string) *pageSizer {
+	s := &pageSizer{AdaptivePaging: a, max: perPage, size: perPage}
+	if page, err := strconv.Atoi(query["page"]); err == nil && page > 1 {
+		s.start = (page - 1) * perPage
+	}
+
+	return s
+}
+
+// apply sets the page and page size of the page following fetched items.
+func (s *pageSizer) apply(query map[string]string, fetched int) {
+	offset := s.start + fetched
+	query["per_page"] = strconv.Itoa(s.size)
+	query["page"] = strconv.Itoa(offset/s.size + 1)
+}
+
+// observe adapts the size to a page that took took and got resp, the page to request
+// next following fetched items, and reports whether the size changed.
+func (s *pageSizer) observe(took time.Duration, resp *http.Response, fetched int) (resized bool) {
+	offset := s.start + fetched
+	degraded := took > s.SlowPage ||
+		(resp != nil && resp.StatusCode >= http.StatusInternalServerError) ||
+		Retries(resp).Attempts > 1
+
+	if degraded {
+		s.healthy = 0
+		return s.resize(s.size/2, s.MinPerPage, offset)
+	}
+
+	if s.healthy++; s.healthy < s.HealthyPages || s.size >= s.max {
+		return false
+	}
+	s.healthy = 0
+
+	return s.resize(min(s.size*2, s.max), s.size+1, offset)
+}
+
+// resize switches to the largest size between from and to, from being the largest, that
+// divides offset.
+func (s *pageSizer) resize(from, to, offset int) bool {
+	for size := from; size >= to && size > 0; size-- {
+		if offset%size == 0 {
+			s.size = size
+			return true
+		}
+	}
+
+	return false
+}
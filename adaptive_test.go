@@ -0,0 +1,71 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAdaptivePaging(t *testing.T) {
+	backoff := pageRetryBackoff
+	t.Cleanup(func() { pageRetryBackoff = backoff })
+	pageRetryBackoff = 0
+
+	sizes := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		sizes = append(sizes, strconv.Itoa(perPage))
+
+		offset := (page - 1) * perPage
+		// Large pages past the 40th item fail.
+		if perPage > 10 && offset >= 40 && offset < 60 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		ids := []string{}
+		for i := offset; i < min(offset+perPage, 100); i++ {
+			ids = append(ids, fmt.Sprintf(`{"id": %d}`, i))
+		}
+		next := "null"
+		if offset+perPage < 100 {
+			next = fmt.Sprintf(`"/widgets?page=%d&per_page=%d"`, page+1, perPage)
+		}
+		fmt.Fprintf(w, `{"data": [%v], "paging": {"page": %d, "per_page": %d, "next": %v}}`, strings.Join(ids, ","), page, perPage, next)
+	}))
+	defer srv.Close()
+
+	client := &Client{token: "test", env: srv.URL, PageRetries: 1}
+	WithAdaptivePaging(AdaptivePaging{MinPerPage: 10, HealthyPages: 2})(client)
+
+	widgets := Endpoint[*testWidgets, *testWidget]{
+		Path:    "/widgets",
+		PerPage: 20,
+		Wrap: func(data []*testWidget, paging *Paging) *testWidgets {
+			return &testWidgets{Data: data, Paging: paging}
+		},
+	}
+
+	all, _, err := widgets.ListAll(context.Background(), client, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all.Data) != 100 {
+		t.Fatalf("expected 100 widgets, got %v", len(all.Data))
+	}
+	for i, w := range all.Data {
+		if w.ID != i {
+			t.Fatalf("expected widget %v at %v, got %v", i, i, w.ID)
+		}
+	}
+
+	if got := strings.Join(sizes, ","); got != "20,20,20,10,10,20,20" {
+		t.Errorf("expected the page size to shrink and grow back, got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// APIError is returned by the Client methods when the API answers with a non 2xx
+// status once retries are exhausted, so that callers can branch on the status instead
+// of matching error strings:
+//
+//	var apiErr *tenkft.APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+//		...
+//	}
+//
+// A 401 also matches ErrInvalidToken with errors.Is.
+type APIError struct {
+	StatusCode int
+	// Message is the message of the error body, the whole body when it has none.
+	Message string
+	// Method and Endpoint identify the call, Endpoint having its IDs replaced by %d,
+	// e.g. "/projects/%d/phases", see RetryHook.
+	Method   string
+	Endpoint string
+	// RetryAfter is the wait the API asked for in its Retry-After header, 0 when absent.
+	RetryAfter time.Duration
+	RequestID  string
+	RawBody    []byte
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("tenkft: %v %v: %v %v", e.Method, e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request id " + e.RequestID + ")"
+	}
+
+	return msg
+}
+
+// Unwrap returns the *utils.StatusError e was built from.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is reports a 401 as ErrInvalidToken.
+func (e *APIError) Is(target error) bool {
+	return target == ErrInvalidToken && e.StatusCode == http.StatusUnauthorized
+}
+
+// apiError turns the *utils.StatusError of a call to rawURL into an *APIError, other
+// errors are returned as is.
+func (c *Client) apiError(method, rawURL string, resp *http.Response, err error) error {
+	var statusErr *utils.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	apiErr := &APIError{
+		StatusCode: statusErr.StatusCode,
+		Message:    errorMessage(statusErr.Body),
+		Method:     method,
+		Endpoint:   endpointOf(c.env, rawURL),
+		RequestID:  statusErr.RequestID,
+		RawBody:    statusErr.Body,
+		err:        err,
+	}
+	if resp != nil {
+		apiErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	return apiErr
+}
+
+// errorMessage returns the message of an error body, {"message": "..."}, or the body.
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Message != "" {
+		return parsed.Message
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+// retryAfter parses a Retry-After header, either seconds or an HTTP date.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return max(0, time.Until(t))
+	}
+
+	return 0
+}
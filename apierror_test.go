@@ -0,0 +1,40 @@
+package tenkft
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+func TestAPIError(t *testing.T) {
+	status := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, `{"message": "not found"}`, status)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	_, _, err := client.GetProjectByID(42, map[string]string{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "not found" || apiErr.Method != http.MethodGet ||
+		apiErr.Endpoint != "/projects/%d" || apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("unexpected APIError %+v", apiErr)
+	}
+	var statusErr *utils.StatusError
+	if !errors.As(err, &statusErr) || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the APIError to wrap the status error only, got %v", err)
+	}
+
+	status = http.StatusUnauthorized
+	if _, _, err := client.GetProjectByID(42, map[string]string{}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected a 401 to be ErrInvalidToken, got %v", err)
+	}
+}
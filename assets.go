@@ -0,0 +1,171 @@
+package tenkft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// Asset is a downloaded thumbnail or secure URL document.
+type Asset struct {
+	URL         string
+	ContentType string
+	Data        []byte
+	FetchedAt   time.Time
+}
+
+// AssetCache downloads project and user thumbnails and project secure URL documents and
+// keeps them in memory for TTL, for tools that render project cards. Expired secure
+// URLs are refreshed by refetching the project first. Concurrent requests for the same
+// asset share a single download.
+type AssetCache struct {
+	TTL time.Duration
+
+	c       *Client
+	mu      sync.Mutex
+	assets  map[string]*Asset
+	flights *flightGroup
+}
+
+// ProjectThumbnail returns the thumbnail of p, or nil if it has none.
+func (ac *AssetCache) ProjectThumbnail(p *Project) (*Asset, error) {
+	return ac.get(p.Thumbnail)
+}
+
+// UserThumbnail returns the thumbnail of u, or nil if it has none.
+func (ac *AssetCache) UserThumbnail(u *User) (*Asset, error) {
+	return ac.get(u.Thumbnail)
+}
+
+// ProjectSecureURL returns the document behind the secure URL of p, or nil if it has
+// none. When the secure URL has expired p is refetched, updating its SecureURL and
+// SecureURLExpiration, before downloading - see Client.RefreshSecureURL.
+func (ac *AssetCache) ProjectSecureURL(p *Project) (*Asset, error) {
+	url, _, err := ac.c.RefreshSecureURL(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ac.get(url)
+}
+
+func (ac *AssetCache) get(url string) (*Asset, error) {
+	if url == "" {
+		return nil, nil
+	}
+
+	ac.mu.Lock()
+	a, ok := ac.assets[url]
+	ac.mu.Unlock()
+
+	if ok && time.Since(a.FetchedAt) < ac.TTL {
+		return a, nil
+	}
+
+	data, resp, err := ac.flights.do(context.Background(), url, func() ([]byte, *http.Response, error) {
+		return ac.download(url)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a = &Asset{URL: url, ContentType: resp.Header.Get("Content-Type"), Data: data, FetchedAt: time.Now()}
+	ac.mu.Lock()
+	ac.assets[url] = a
+	ac.mu.Unlock()
+
+	return a, nil
+}
+
+// download GETs url without holding ac.mu, so that other assets can be served meanwhile.
+func (ac *AssetCache) download(url string) ([]byte, *http.Response, error) {
+	hc := ac.c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := readAll(resp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, resp, fmt.Errorf("Non OK status Code: %v downloading %v", resp.StatusCode, url)
+	}
+
+	return data, resp, nil
+}
+
+// SetUserThumbnail uploads the image read from r, of contentType e.g. "image/jpeg", as
+// the thumbnail of u and updates u from the response, including its Thumbnail URL.
+func (c *Client) SetUserThumbnail(u *User, r io.Reader, contentType string) (*http.Response, error) {
+	return c.SetUserThumbnailCtx(context.Background(), u, r, contentType)
+}
+
+// SetUserThumbnailCtx is SetUserThumbnail honouring ctx.
+func (c *Client) SetUserThumbnailCtx(ctx context.Context, u *User, r io.Reader, contentType string) (*http.Response, error) {
+	return c.uploadThumbnail(ctx, c.env+"/users/"+strconv.Itoa(int(u.ID))+"/thumbnail", r, contentType, u)
+}
+
+// SetProjectThumbnail uploads the image read from r as the thumbnail of p, see
+// SetUserThumbnail.
+func (c *Client) SetProjectThumbnail(p *Project, r io.Reader, contentType string) (*http.Response, error) {
+	return c.SetProjectThumbnailCtx(context.Background(), p, r, contentType)
+}
+
+// SetProjectThumbnailCtx is SetProjectThumbnail honouring ctx.
+func (c *Client) SetProjectThumbnailCtx(ctx context.Context, p *Project, r io.Reader, contentType string) (*http.Response, error) {
+	return c.uploadThumbnail(ctx, c.env+"/projects/"+strconv.Itoa(int(p.ID))+"/thumbnail", r, contentType, p)
+}
+
+// uploadThumbnail POSTs the image read from r to url as a multipart form and decodes
+// the response into out.
+func (c *Client) uploadThumbnail(ctx context.Context, url string, r io.Reader, contentType string, out interface{}) (resp *http.Response, err error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="thumbnail"; filename="thumbnail"`)
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return
+	}
+	if _, err = io.Copy(part, r); err != nil {
+		return
+	}
+	if err = form.Close(); err != nil {
+		return
+	}
+
+	fetcher, err := utils.NewFetchOpts(url, http.MethodPost, "", c.headers(), c.MaxRetries)
+	if err != nil {
+		return
+	}
+	fetcher.RawBody = body.Bytes()
+	fetcher.ContentType = form.FormDataContentType()
+
+	resp, err = c.send(ctx, fetcher)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	err = c.decodeBody(resp.Body, out)
+
+	return
+}
@@ -0,0 +1,127 @@
+package tenkft
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAssetCacheRefreshesSecureURL(t *testing.T) {
+	downloads := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/1":
+			expiration := time.Now().Add(time.Hour).Format(time.RFC3339)
+			fmt.Fprintf(w, `{"id": 1, "secureurl": "%v/secure/new", "secureurl_expiration": "%v"}`, srv.URL, expiration)
+		case "/secure/new":
+			downloads++
+			fmt.Fprint(w, "card")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ac := NewAssetCache(&Client{token: "test", env: srv.URL}, time.Hour)
+	p := &Project{ID: 1, SecureURL: srv.URL + "/secure/old", SecureURLExpiration: "2018-01-01T00:00:00Z"}
+
+	for i := 0; i < 2; i++ {
+		a, err := ac.ProjectSecureURL(p)
+		if err != nil {
+			t.Fatal("could not get secure url", err)
+		}
+		if string(a.Data) != "card" {
+			t.Errorf("expected the refreshed document, got %q", a.Data)
+		}
+	}
+
+	if p.SecureURL != srv.URL+"/secure/new" {
+		t.Errorf("expected the project secure url to be refreshed, got %v", p.SecureURL)
+	}
+	if downloads != 1 {
+		t.Errorf("expected a single download, got %v", downloads)
+	}
+}
+
+func TestAssetCacheConcurrentDownloads(t *testing.T) {
+	var downloads int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		if r.URL.Path == "/slow.png" {
+			<-release
+		}
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer srv.Close()
+	ac := NewAssetCache(&Client{token: "test", env: srv.URL}, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if a, err := ac.UserThumbnail(&User{Thumbnail: srv.URL + "/slow.png"}); err != nil || string(a.Data) != "/slow.png" {
+				t.Errorf("expected the slow thumbnail, got %+v, %v", a, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Another asset isn't held up by the slow download.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if a, err := ac.ProjectThumbnail(&Project{Thumbnail: srv.URL + "/fast.png"}); err != nil || a.ContentType != "image/png" {
+			t.Errorf("expected the fast thumbnail, got %+v, %v", a, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected the fast thumbnail not to wait for the slow one")
+	}
+
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&downloads); n != 2 {
+		t.Errorf("expected a single download per asset, got %v", n)
+	}
+}
+
+func TestSetUserThumbnail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("thumbnail")
+		if r.Method != http.MethodPost || r.URL.Path != "/users/7/thumbnail" || err != nil {
+			http.Error(w, "unexpected upload", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		data, _ := io.ReadAll(file)
+		if header.Header.Get("Content-Type") != "image/jpeg" || string(data) != "jpeg data" {
+			http.Error(w, "unexpected thumbnail", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"id": 7, "thumbnail": "https://example.com/7.jpg"}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	u := NewUser()
+	u.ID = 7
+	if _, err := client.SetUserThumbnail(u, strings.NewReader("jpeg data"), "image/jpeg"); err != nil {
+		t.Fatal("could not upload thumbnail", err)
+	}
+	if u.Thumbnail != "https://example.com/7.jpg" {
+		t.Errorf("expected the user to be updated, got %v", u.Thumbnail)
+	}
+}
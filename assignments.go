@@ -0,0 +1,90 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// assignmentWorkers is the number of users whose assignments GetAllAssignments fetches
+// at once.
+const assignmentWorkers = 4
+
+// GetAllAssignments returns the assignments of every user overlapping from and to,
+// merged into one collection ordered by user. The users' assignments are fetched
+// concurrently, opts being sent with each of those requests. Assignments of placeholder
+// resources aren't included. On error resp is the response of the failing call and the
+// assignments fetched are not returned.
+func (c *Client) GetAllAssignments(from, to time.Time, opts map[string]string) (*Assignments, *http.Response, error) {
+	return c.GetAllAssignmentsCtx(context.Background(), from, to, opts)
+}
+
+// GetAllAssignmentsCtx is GetAllAssignments honouring ctx.
+func (c *Client) GetAllAssignmentsCtx(ctx context.Context, from, to time.Time, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	assignments = NewAssignments()
+
+	users, resp, err := usersEndpoint.ListAll(ctx, c, map[string]string{})
+	if err != nil {
+		return
+	}
+
+	query := map[string]string{}
+	for k, v := range opts {
+		query[k] = v
+	}
+	query["from"], query["to"] = from.Format(DateFormat), to.Format(DateFormat)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		perUser = make([][]*Assignment, len(users.Data))
+		next    = make(chan int)
+	)
+	for w := 0; w < assignmentWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				list, r, listErr := userAssignmentsEndpoint.ListAll(ctx, c, query, int(users.Data[i].ID))
+				if listErr != nil {
+					once.Do(func() {
+						resp, err = r, fmt.Errorf("user %v: %w", users.Data[i].ID, listErr)
+						cancel()
+					})
+					continue
+				}
+				perUser[i] = list.Data
+			}
+		}()
+	}
+
+	for i := range users.Data {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return
+	}
+
+	for _, list := range perUser {
+		assignments.Data = append(assignments.Data, list...)
+	}
+	assignments.Paging.TotalRecords = len(assignments.Data)
+
+	err = c.expand(assignments)
+
+	return
+}
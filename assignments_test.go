@@ -0,0 +1,76 @@
+package tenkft
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetAllAssignments(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/users" {
+			fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}, {"id": 3}], "paging": {"next": null}}`)
+			return
+		}
+
+		q := r.URL.Query()
+		if q.Get("from") != "2024-03-01" || q.Get("to") != "2024-03-31" || q.Get("with_phases") != "true" {
+			http.Error(w, "unexpected query "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+
+		var userID int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/users/"), "%d", &userID)
+		if userID == 2 {
+			fmt.Fprint(w, `{"data": [], "paging": {"next": null}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": [{"id": %d, "user_id": %d}, {"id": %d, "user_id": %d}], "paging": {"next": null}}`, userID*10, userID, userID*10+1, userID)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	assignments, _, err := client.GetAllAssignments(from, from.AddDate(0, 1, -1), map[string]string{"with_phases": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []AssignmentID{}
+	for _, a := range assignments.Data {
+		ids = append(ids, a.ID)
+	}
+	if fmt.Sprint(ids) != "[10 11 30 31]" {
+		t.Errorf("expected the assignments ordered by user, got %v", ids)
+	}
+	if requests != 4 {
+		t.Errorf("expected one request per user, got %v", requests)
+	}
+}
+
+func TestGetAllAssignmentsFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users" {
+			fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {"next": null}}`)
+			return
+		}
+		if r.URL.Path == "/users/2/assignments" {
+			http.Error(w, "nope", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1}], "paging": {"next": null}}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	_, resp, err := client.GetAllAssignments(time.Now(), time.Now(), map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "user 2") || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected user 2 to fail with a 403, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+)
+
+// GetProjectBudgetItems abstraction to GET /projects/<id>/budget_items, opts may filter
+// on item_type.
+func (c *Client) GetProjectBudgetItems(p *Project, opts map[string]string) (*BudgetItems, *http.Response, error) {
+	return c.GetProjectBudgetItemsCtx(context.Background(), p, opts)
+}
+
+// GetProjectBudgetItemsCtx is GetProjectBudgetItems honouring ctx.
+func (c *Client) GetProjectBudgetItemsCtx(ctx context.Context, p *Project, opts map[string]string) (*BudgetItems, *http.Response, error) {
+	return budgetItemsEndpoint.List(ctx, c, opts, int(p.ID))
+}
+
+// GetAllProjectBudgetItems returns all budget items of a project - automatically
+// paginates and returns accumulated budget items. resp and err correspond to the latest
+// one in the loop.
+func (c *Client) GetAllProjectBudgetItems(p *Project, opts map[string]string) (*BudgetItems, *http.Response, error) {
+	return c.GetAllProjectBudgetItemsCtx(context.Background(), p, opts)
+}
+
+// GetAllProjectBudgetItemsCtx is GetAllProjectBudgetItems honouring ctx, see
+// Endpoint.ListAll for what is returned when it is canceled.
+func (c *Client) GetAllProjectBudgetItemsCtx(ctx context.Context, p *Project, opts map[string]string) (*BudgetItems, *http.Response, error) {
+	return budgetItemsEndpoint.ListAll(ctx, c, opts, int(p.ID))
+}
+
+// GetProjectBudgetItem abstraction to GET /projects/<id>/budget_items/<id>
+func (c *Client) GetProjectBudgetItem(pID ProjectID, id int, opts map[string]string) (*BudgetItem, *http.Response, error) {
+	return c.GetProjectBudgetItemCtx(context.Background(), pID, id, opts)
+}
+
+// GetProjectBudgetItemCtx is GetProjectBudgetItem honouring ctx.
+func (c *Client) GetProjectBudgetItemCtx(ctx context.Context, pID ProjectID, id int, opts map[string]string) (*BudgetItem, *http.Response, error) {
+	return budgetItemsEndpoint.Get(ctx, c, id, opts, int(pID))
+}
+
+// CreateProjectBudgetItem abstraction to POST /projects/<id>/budget_items
+func (c *Client) CreateProjectBudgetItem(pID ProjectID, bi *BudgetItem) (*http.Response, error) {
+	return c.CreateProjectBudgetItemCtx(context.Background(), pID, bi)
+}
+
+// CreateProjectBudgetItemCtx is CreateProjectBudgetItem honouring ctx.
+func (c *Client) CreateProjectBudgetItemCtx(ctx context.Context, pID ProjectID, bi *BudgetItem) (*http.Response, error) {
+	return budgetItemsEndpoint.Create(ctx, c, bi.baseBudgetItem, bi, int(pID))
+}
+
+// UpdateProjectBudgetItem abstraction to PUT /projects/<id>/budget_items/<id>
+func (c *Client) UpdateProjectBudgetItem(bi *BudgetItem) (*http.Response, error) {
+	return c.UpdateProjectBudgetItemCtx(context.Background(), bi)
+}
+
+// UpdateProjectBudgetItemCtx is UpdateProjectBudgetItem honouring ctx.
+func (c *Client) UpdateProjectBudgetItemCtx(ctx context.Context, bi *BudgetItem) (*http.Response, error) {
+	return budgetItemsEndpoint.Update(ctx, c, bi.ID, bi.baseBudgetItem, bi, int(bi.AssignableID))
+}
+
+// DeleteProjectBudgetItem abstraction to DELETE /projects/<id>/budget_items/<id>
+func (c *Client) DeleteProjectBudgetItem(bi *BudgetItem) (*http.Response, error) {
+	return c.DeleteProjectBudgetItemCtx(context.Background(), bi)
+}
+
+// DeleteProjectBudgetItemCtx is DeleteProjectBudgetItem honouring ctx.
+func (c *Client) DeleteProjectBudgetItemCtx(ctx context.Context, bi *BudgetItem) (*http.Response, error) {
+	return budgetItemsEndpoint.Delete(ctx, c, bi.ID, int(bi.AssignableID))
+}
@@ -0,0 +1,122 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// throttleRequeues is the number of times UpdateMany puts an item throttled with a
+// 429 back in the queue before giving up on it.
+const throttleRequeues = 3
+
+// defaultRequeueBackoff is the wait before sending a throttled item again, it grows
+// with every attempt, see Client.requeueBackoff.
+const defaultRequeueBackoff = 10 * time.Second
+
+// UpdateMany updates items with up to concurrency requests in flight, adapting to the
+// rate limit: concurrency is halved whenever a call is throttled or retried and grows
+// back by one after as many successes in a row as requests in flight. Items failing
+// with a 429 are retried once the pace has slowed down. errs is indexed like items,
+// nil when every update succeeded. When ctx is canceled the items not sent yet fail
+// with ctx.Err().
+func UpdateMany[T *Project | *User | *Assignment](ctx context.Context, c *Client, items []T, concurrency int) (errs []error) {
+	type result struct {
+		i         int
+		throttled bool
+		err       error
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		results   = make(chan result)
+		queue     = make([]int, len(items))
+		requeued  = make([]int, len(items))
+		all       = make([]error, len(items))
+		failed    = false
+		limit     = concurrency
+		inFlight  = 0
+		successes = 0
+	)
+	for i := range items {
+		queue[i] = i
+	}
+
+	for len(queue) > 0 || inFlight > 0 {
+		for inFlight < limit && len(queue) > 0 && ctx.Err() == nil {
+			i := queue[0]
+			queue = queue[1:]
+			inFlight++
+
+			go func(i int, wait time.Duration) {
+				if err := utils.Sleep(ctx, wait); err != nil {
+					results <- result{i: i, err: err}
+					return
+				}
+
+				resp, err := update(ctx, c, items[i])
+				throttled := resp != nil && (resp.StatusCode == http.StatusTooManyRequests || Retries(resp).Attempts > 1)
+				results <- result{i: i, throttled: throttled, err: err}
+			}(i, time.Duration(requeued[i])*c.requeueBackoff)
+		}
+
+		if inFlight == 0 {
+			// ctx is canceled, fail what is left.
+			for _, i := range queue {
+				all[i] = ctx.Err()
+			}
+			failed = true
+			break
+		}
+
+		r := <-results
+		inFlight--
+
+		if r.throttled {
+			limit = max(1, limit/2)
+			successes = 0
+		} else if r.err == nil {
+			if successes++; successes >= limit && limit < concurrency {
+				limit++
+				successes = 0
+			}
+		}
+
+		if r.err != nil && r.throttled && requeued[r.i] < throttleRequeues && ctx.Err() == nil {
+			requeued[r.i]++
+			queue = append(queue, r.i)
+			continue
+		}
+
+		if r.err != nil {
+			all[r.i] = fmt.Errorf("item %v: %w", r.i, r.err)
+			failed = true
+		}
+	}
+
+	if failed {
+		errs = all
+	}
+
+	return
+}
+
+// update PUTs item to its endpoint.
+func update[T *Project | *User | *Assignment](ctx context.Context, c *Client, item T) (*http.Response, error) {
+	switch item := any(item).(type) {
+	case *Project:
+		return projectsEndpoint.Update(ctx, c, int(item.ID), item.baseProject, item)
+	case *User:
+		return usersEndpoint.Update(ctx, c, int(item.ID), item.baseUser, item)
+	case *Assignment:
+		return userAssignmentsEndpoint.Update(ctx, c, int(item.ID), item.baseAssignment, item, int(item.UserID))
+	}
+
+	return nil, fmt.Errorf("cannot update %T", item)
+}
@@ -0,0 +1,64 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestUpdateMany(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+		calls    = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		calls[r.URL.Path]++
+		n := calls[r.URL.Path]
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		switch {
+		case r.Method != http.MethodPut:
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+		case r.URL.Path == "/projects/2" && n == 1:
+			http.Error(w, `{"message": "slow down"}`, http.StatusTooManyRequests)
+		case r.URL.Path == "/projects/3":
+			http.Error(w, `{"message": "invalid"}`, http.StatusUnprocessableEntity)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	projects := []*Project{NewProject(), NewProject(), NewProject(), NewProject()}
+	for i, p := range projects {
+		p.ID = ProjectID(i + 1)
+	}
+
+	errs := UpdateMany(context.Background(), client, projects, 2)
+	if len(errs) != 4 || errs[0] != nil || errs[1] != nil || errs[2] == nil || errs[3] != nil {
+		t.Errorf("expected only project 3 to fail, got %v", errs)
+	}
+	if calls["/projects/2"] != 2 {
+		t.Errorf("expected the throttled project to be sent again, got %v calls", calls["/projects/2"])
+	}
+	if peak > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %v", peak)
+	}
+
+	if errs := UpdateMany(context.Background(), client, projects[:2], 2); errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,179 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNoWorkingDay is returned by SplitAssignment when the assignment only covers days
+// off.
+var ErrNoWorkingDay = errors.New("tenkft: no working day in the assignment")
+
+// DefaultWorkingHours is the schedule of users without availabilities: 8 hours Monday
+// through Friday. Indexes are time.Weekday values, like the API's day0 to day6.
+var DefaultWorkingHours = [7]float64{0, 8, 8, 8, 8, 8, 0}
+
+// Calendar answers how many hours people can work on given days, combining account
+// holidays, each user's availabilities and custom blackout dates. Users need their
+// availabilities fetched (fields=availabilities) for their own schedule to be used.
+type Calendar struct {
+	// DefaultHours is the schedule of users with no availability covering a day.
+	DefaultHours [7]float64
+
+	loc       *time.Location
+	holidays  map[string]string
+	blackouts map[string]bool
+}
+
+// GetCalendar fetches the account holidays and returns a Calendar in the client's
+// time zone.
+func (c *Client) GetCalendar() (*Calendar, *http.Response, error) {
+	return c.GetCalendarCtx(context.Background())
+}
+
+// GetCalendarCtx is GetCalendar honouring ctx.
+func (c *Client) GetCalendarCtx(ctx context.Context) (cal *Calendar, resp *http.Response, err error) {
+	holidays, resp, err := c.GetAllHolidaysCtx(ctx, map[string]string{})
+	if err != nil {
+		return
+	}
+
+	cal = NewCalendar(c.Location(), holidays)
+
+	return
+}
+
+// AddBlackout marks days on which nobody works, on top of the account holidays.
+func (cal *Calendar) AddBlackout(days ...time.Time) {
+	for _, day := range days {
+		cal.blackouts[cal.key(day)] = true
+	}
+}
+
+// Holiday returns the name of the holiday on day and whether there is one.
+func (cal *Calendar) Holiday(day time.Time) (name string, ok bool) {
+	name, ok = cal.holidays[cal.key(day)]
+	return
+}
+
+// IsWorkingDay reports whether day is neither a holiday nor a blackout.
+func (cal *Calendar) IsWorkingDay(day time.Time) bool {
+	key := cal.key(day)
+	_, holiday := cal.holidays[key]
+
+	return !holiday && !cal.blackouts[key]
+}
+
+// WorkingHours returns the hours u can work on day. A nil user follows DefaultHours.
+func (cal *Calendar) WorkingHours(u *User, day time.Time) float64 {
+	if !cal.IsWorkingDay(day) {
+		return 0
+	}
+
+	return cal.scheduledHours(u, day)
+}
+
+// scheduledHours returns the hours u works on day per their weekly schedule, holidays
+// and blackouts aside.
+func (cal *Calendar) scheduledHours(u *User, day time.Time) float64 {
+	weekday := day.In(cal.loc).Weekday()
+	if u != nil {
+		key := cal.key(day)
+		for _, a := range u.Availabilities.Data {
+			if (a.StartsAt == "" || a.StartsAt <= key) && (a.EndsAt == "" || key <= a.EndsAt) {
+				return a.hours(weekday)
+			}
+		}
+	}
+
+	return cal.DefaultHours[weekday]
+}
+
+// WorkingHoursBetween returns the hours u can work from the day of from through the
+// day of to, both inclusive.
+func (cal *Calendar) WorkingHoursBetween(u *User, from, to time.Time) float64 {
+	var hours float64
+	for day := cal.day(from); !day.After(cal.day(to)); day = day.AddDate(0, 0, 1) {
+		hours += cal.WorkingHours(u, day)
+	}
+
+	return hours
+}
+
+// WorkingDaysBetween returns the days from the day of from through the day of to on
+// which u can work at all.
+func (cal *Calendar) WorkingDaysBetween(u *User, from, to time.Time) []time.Time {
+	days := []time.Time{}
+	for day := cal.day(from); !day.After(cal.day(to)); day = day.AddDate(0, 0, 1) {
+		if cal.WorkingHours(u, day) > 0 {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// SplitAssignment splits an hours per day assignment around the holidays and blackouts
+// falling on days u would work, so that they aren't booked. Days u doesn't work anyway,
+// e.g. weekends, don't split the assignment as the API doesn't book them. u may be nil,
+// see WorkingHours. Other allocation modes already follow availability and are returned
+// as is. ErrNoWorkingDay is returned when u can't work any day of the assignment.
+func (cal *Calendar) SplitAssignment(a *Assignment, u *User) ([]*Assignment, error) {
+	if a.AllocationMode != AllocationHoursPerDay {
+		return []*Assignment{a}, nil
+	}
+
+	startsAt, err := time.ParseInLocation(DateFormat, a.StartsAt, cal.loc)
+	if err != nil {
+		return nil, err
+	}
+	endsAt, err := time.ParseInLocation(DateFormat, a.EndsAt, cal.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*Assignment{}
+	var run *Assignment
+	for day := startsAt; !day.After(endsAt); day = day.AddDate(0, 0, 1) {
+		switch {
+		case cal.WorkingHours(u, day) > 0:
+			if run != nil {
+				run.EndsAt = cal.key(day)
+				continue
+			}
+
+			clone := *a
+			clone.baseAssignment = &baseAssignment{}
+			*clone.baseAssignment = *a.baseAssignment
+			clone.StartsAt, clone.EndsAt = cal.key(day), cal.key(day)
+			run = &clone
+			runs = append(runs, run)
+		case cal.scheduledHours(u, day) > 0:
+			// A holiday or blackout on a day u would work ends the run.
+			run = nil
+		}
+	}
+
+	if len(runs) == 0 {
+		return nil, ErrNoWorkingDay
+	}
+
+	return runs, nil
+}
+
+func (cal *Calendar) day(t time.Time) time.Time {
+	t = t.In(cal.loc)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, cal.loc)
+}
+
+func (cal *Calendar) key(t time.Time) string {
+	return t.In(cal.loc).Format(DateFormat)
+}
+
+// hours returns the availability for a weekday.
+func (a *Availability) hours(weekday time.Weekday) float64 {
+	return [7]float64{a.Day0, a.Day1, a.Day2, a.Day3, a.Day4, a.Day5, a.Day6}[weekday]
+}
@@ -0,0 +1,70 @@
+package tenkft
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCalendarWorkingHoursBetween(t *testing.T) {
+	holidays := &Holidays{Data: []*Holiday{{Date: "2018-03-13", Name: "Founders day"}}}
+	cal := NewCalendar(time.UTC, holidays)
+	cal.AddBlackout(time.Date(2018, 3, 14, 0, 0, 0, 0, time.UTC))
+
+	from := time.Date(2018, 3, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2018, 3, 18, 0, 0, 0, 0, time.UTC)
+
+	// Monday to Sunday, minus a holiday and a blackout day.
+	if hours := cal.WorkingHoursBetween(nil, from, to); hours != 24 {
+		t.Errorf("expected 24 default working hours, got %v", hours)
+	}
+
+	partTimer := &User{Availabilities: Availabilities{Data: []*Availability{
+		{StartsAt: "2018-01-01", Day1: 4, Day2: 4, Day3: 4, Day4: 4, Day5: 4},
+	}}}
+	if hours := cal.WorkingHoursBetween(partTimer, from, to); hours != 12 {
+		t.Errorf("expected 12 part time working hours, got %v", hours)
+	}
+}
+
+func TestSplitAssignment(t *testing.T) {
+	holidays := &Holidays{Data: []*Holiday{{Date: "2018-03-07", Name: "Founders day"}}}
+	cal := NewCalendar(time.UTC, holidays)
+
+	a := NewAssignment()
+	a.AllocationMode, a.HoursPerDay = "hours_per_day", 4
+	a.StartsAt, a.EndsAt = "2018-03-05", "2018-03-13"
+
+	runs, err := cal.SplitAssignment(a, nil)
+	if err != nil {
+		t.Fatal("could not split", err)
+	}
+
+	got := []string{}
+	for _, r := range runs {
+		got = append(got, r.StartsAt+".."+r.EndsAt)
+	}
+	// The holiday splits the assignment, the weekend doesn't.
+	expected := "[2018-03-05..2018-03-06 2018-03-08..2018-03-13]"
+	if fmt.Sprint(got) != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if a.StartsAt != "2018-03-05" || a.EndsAt != "2018-03-13" || runs[0].HoursPerDay != 4 {
+		t.Error("expected the runs to be copies of the assignment")
+	}
+}
+
+func TestSplitAssignmentNoWorkingDay(t *testing.T) {
+	holidays := &Holidays{Data: []*Holiday{{Date: "2018-03-09", Name: "Founders day"}}}
+	cal := NewCalendar(time.UTC, holidays)
+	cal.AddBlackout(time.Date(2018, 3, 12, 0, 0, 0, 0, time.UTC))
+
+	a := NewAssignment()
+	a.AllocationMode, a.HoursPerDay = "hours_per_day", 4
+	a.StartsAt, a.EndsAt = "2018-03-09", "2018-03-12"
+
+	// Friday holiday, weekend and Monday blackout.
+	if runs, err := cal.SplitAssignment(a, nil); err != ErrNoWorkingDay {
+		t.Errorf("expected ErrNoWorkingDay, got %v, %v", runs, err)
+	}
+}
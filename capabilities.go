@@ -0,0 +1,100 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// Capability is a feature of the API not every 10000ft plan exposes, named after the
+// collection serving it.
+type Capability string
+
+// Capabilities probed by Client.Capabilities.
+const (
+	CapBillRates            Capability = "bill_rates"
+	CapCustomFields         Capability = "custom_fields"
+	CapTimeEntries          Capability = "time_entries"
+	CapApprovals            Capability = "approvals"
+	CapPlaceholderResources Capability = "placeholder_resources"
+)
+
+var capabilities = []Capability{CapBillRates, CapCustomFields, CapTimeEntries, CapApprovals, CapPlaceholderResources}
+
+// ErrNotSupported is returned, wrapped, by calls to a Capability the account lacks
+// once Client.Capabilities found it out, instead of the API's 404.
+var ErrNotSupported = errors.New("tenkft: not supported by the account")
+
+// Capabilities tells which Capabilities the account of a client supports.
+type Capabilities map[Capability]bool
+
+// capabilitySet is the Capabilities recorded on a client.
+type capabilitySet struct {
+	mu   sync.Mutex
+	caps Capabilities
+}
+
+// Capabilities probes every Capability with a GET of a single item and records
+// what the token's account supports, so that calls to the others fail with
+// ErrNotSupported without reaching the API. A Capability answered with a 403 or a 404
+// is unsupported.
+func (c *Client) Capabilities(ctx context.Context) (caps Capabilities, err error) {
+	caps = Capabilities{}
+	for _, capability := range capabilities {
+		if caps[capability], err = c.probe(ctx, "/"+string(capability)); err != nil {
+			return nil, fmt.Errorf("probing %v: %w", capability, err)
+		}
+	}
+
+	c.capabilities.mu.Lock()
+	c.capabilities.caps = caps
+	c.capabilities.mu.Unlock()
+
+	return
+}
+
+// probe reports whether the collection at path is served, without retries.
+func (c *Client) probe(ctx context.Context, path string) (bool, error) {
+	fetcher, err := utils.NewFetchOpts(c.env+path+"?per_page=1", http.MethodGet, "", c.headers(), 0)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.send(ctx, fetcher)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+			return false, nil
+		}
+		return false, err
+	}
+	resp.Body.Close()
+
+	return true, nil
+}
+
+// supports returns an error wrapping ErrNotSupported when the request URL rawURL goes
+// to a collection, or an item or sub-collection of it, of a Capability the account was
+// found to lack.
+func (c *Client) supports(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		if supported, known := c.capabilities.caps[Capability(segment)]; known && !supported {
+			return fmt.Errorf("%v: %w", segment, ErrNotSupported)
+		}
+	}
+
+	return nil
+}
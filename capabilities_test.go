@@ -0,0 +1,55 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/bill_rates", "/custom_fields":
+			http.NotFound(w, r)
+		case "/approvals":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			fmt.Fprint(w, `{"data": [], "paging": {}}`)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL, MaxRetries: 3}
+
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal("could not probe capabilities", err)
+	}
+	if caps[CapBillRates] || caps[CapCustomFields] || caps[CapApprovals] || !caps[CapTimeEntries] || !caps[CapPlaceholderResources] {
+		t.Errorf("unexpected capabilities %v", caps)
+	}
+	if requests != 5 {
+		t.Errorf("expected a single request per capability, got %v", requests)
+	}
+
+	requests = 0
+	rates, _, err := client.GetAllProjectBillRates(1, map[string]string{})
+	if !errors.Is(err, ErrNotSupported) || rates.Data == nil || requests != 0 {
+		t.Errorf("expected ErrNotSupported without a request, got %v after %v requests", err, requests)
+	}
+	if _, _, err := client.GetTimeEntries(map[string]string{}); err != nil {
+		t.Error("expected supported calls to go through", err)
+	}
+
+	// Calls bypassing the endpoints are gated too.
+	client.capabilities.caps[CapTimeEntries] = false
+	requests = 0
+	var entries struct{ Data []map[string]interface{} }
+	if _, err := client.GetTimeEntriesInto(map[string]string{}, &entries); !errors.Is(err, ErrNotSupported) || requests != 0 {
+		t.Errorf("expected ErrNotSupported without a request, got %v after %v requests", err, requests)
+	}
+}
@@ -0,0 +1,449 @@
+// Command tenkft-proxy serves a simplified read-only JSON API backed by a single
+// tenkft.Client, so that several internal apps share one cache and one rate limit
+// budget instead of each calling 10000ft directly. Collections are returned fully
+// paginated as {"data": [...]}.
+//
+// Usage:
+//
+//	TENKFT_TOKEN=... tenkft-proxy -addr :8080 -env production -rate 2 -ttl 1m -stale 10m -max-entries 1000 -preload users,projects
+//
+// Routes:
+//
+//	GET /projects
+//	GET /projects/{id}
+//	GET /projects/{id}/assignments
+//	GET /projects/{id}/users
+//	GET /users
+//	GET /users/{id}/assignments
+//	GET /leave_types
+//	GET /roles
+//	GET /disciplines
+//	GET /holidays
+//
+// Query parameters are passed on to 10000ft. The rate limit applies to every upstream
+// call, each page of a collection included, and concurrent misses on the same URL share
+// a single fetch. With -stale,
+// expired responses keep being served immediately while a single background refresh
+// updates them, keeping dashboard latency low. -preload fetches the given collections,
+// without query parameters, into the cache before listening so that the first users
+// don't pay for cold misses. The cache holds at most -max-entries responses, entries
+// past their -ttl and -stale being dropped first, then the least recently used ones.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	env := flag.String("env", "production", "10000ft environment, production, staging, rm or rm-eu")
+	rate := flag.Float64("rate", 2, "maximum cache misses forwarded to 10000ft per second, shared by all clients")
+	ttl := flag.Duration("ttl", time.Minute, "how long responses are cached")
+	stale := flag.Duration("stale", 0, "how long expired responses may still be served while being refreshed in the background")
+	maxEntries := flag.Int("max-entries", 1000, "maximum number of responses cached")
+	preload := flag.String("preload", "", "comma separated collections to cache on startup, e.g. users,projects,roles")
+	flag.Parse()
+
+	if *rate <= 0 {
+		log.Fatalf("-rate must be positive, got %v", *rate)
+	}
+	if *maxEntries <= 0 {
+		log.Fatalf("-max-entries must be positive, got %v", *maxEntries)
+	}
+
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	transport := &limitedTransport{limiter: limiter.C, next: http.DefaultTransport}
+
+	profile := tenkft.Profile{Token: os.Getenv("TENKFT_TOKEN"), Env: *env}
+	c, err := profile.NewClient(tenkft.WithMaxRetries(3), tenkft.WithRequestCoalescing(), tenkft.WithTransport(transport))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := &proxy{
+		c:           c,
+		ttl:         *ttl,
+		stale:       *stale,
+		maxEntries:  *maxEntries,
+		now:         time.Now,
+		cache:       map[string]cached{},
+		refreshes:   map[string]*refreshCall{},
+		collections: map[resourceKind]fetchFunc{},
+	}
+	mux := p.routes()
+
+	if *preload != "" {
+		resources := []resourceKind{}
+		for _, name := range strings.Split(*preload, ",") {
+			resources = append(resources, resourceKind("/"+strings.TrimSpace(name)))
+		}
+		if err := p.Preload(context.Background(), resources...); err != nil {
+			log.Printf("preload: %v", err)
+		}
+	}
+
+	log.Printf("tenkft-proxy listening on %v", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+type cached struct {
+	body       []byte
+	expiresAt  time.Time
+	usedAt     time.Time
+	refreshing bool
+}
+
+// limitedTransport sends requests to next at the pace of limiter, so that every call
+// to 10000ft shares the rate limit.
+type limitedTransport struct {
+	limiter <-chan time.Time
+	next    http.RoundTripper
+}
+
+func (t *limitedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	select {
+	case <-t.limiter:
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	}
+
+	return t.next.RoundTrip(r)
+}
+
+// proxy serves cached responses and lets cache misses through to 10000ft.
+type proxy struct {
+	c          *tenkft.Client
+	ttl        time.Duration
+	stale      time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cached
+	// refreshes are the refreshes in progress by key.
+	refreshes map[string]*refreshCall
+
+	// collections are the routes without path parameters, the ones Preload accepts.
+	collections map[resourceKind]fetchFunc
+}
+
+// resourceKind is a collection route, e.g. "/users".
+type resourceKind string
+
+// fetchFunc calls 10000ft for a request, opts holding its query parameters.
+type fetchFunc func(r *http.Request, opts map[string]string) (interface{}, error)
+
+func (p *proxy) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	p.collection(mux, "/projects", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		projects, _, err := p.c.GetAllProjectsCtx(r.Context(), opts)
+		return projects, err
+	})
+	mux.Handle("GET /projects/{id}", p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return nil, err
+		}
+		project, _, err := p.c.GetProjectByIDCtx(r.Context(), tenkft.ProjectID(id), opts)
+		return project, err
+	}))
+	mux.Handle("GET /projects/{id}/assignments", p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return nil, err
+		}
+		assignments, _, err := p.c.GetProjectAssignmentsCtx(r.Context(), &tenkft.Project{ID: tenkft.ProjectID(id)}, opts)
+		return assignments, err
+	}))
+	mux.Handle("GET /projects/{id}/users", p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return nil, err
+		}
+		users, _, err := p.c.GetProjectUsersCtx(r.Context(), tenkft.ProjectID(id), opts)
+		return users, err
+	}))
+	p.collection(mux, "/users", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		users, _, err := p.c.GetAllUsersCtx(r.Context(), opts)
+		return users, err
+	})
+	mux.Handle("GET /users/{id}/assignments", p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return nil, err
+		}
+		assignments, _, err := p.c.GetAllUserAssignmentsCtx(r.Context(), &tenkft.User{ID: tenkft.UserID(id)}, opts)
+		return assignments, err
+	}))
+	p.collection(mux, "/leave_types", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		leaveTypes, _, err := p.c.GetAllLeaveTypesCtx(r.Context(), opts)
+		return leaveTypes, err
+	})
+	p.collection(mux, "/roles", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		roles, _, err := p.c.GetAllRolesCtx(r.Context(), opts)
+		return roles, err
+	})
+	p.collection(mux, "/disciplines", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		disciplines, _, err := p.c.GetAllDisciplinesCtx(r.Context(), opts)
+		return disciplines, err
+	})
+	p.collection(mux, "/holidays", func(r *http.Request, opts map[string]string) (interface{}, error) {
+		holidays, _, err := p.c.GetAllHolidaysCtx(r.Context(), opts)
+		return holidays, err
+	})
+
+	return mux
+}
+
+// badRequest is an error in the request rather than upstream.
+type badRequest struct {
+	error
+}
+
+// pathID returns the {id} path parameter of r.
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, badRequest{fmt.Errorf("invalid id %q", r.PathValue("id"))}
+	}
+
+	return id, nil
+}
+
+// status returns the status code a fetch error is answered with: 400 for bad
+// requests, the status of 10000ft's answer for API errors, 502 otherwise.
+func status(err error) int {
+	var apiErr *tenkft.APIError
+	switch {
+	case errors.As(err, &badRequest{}):
+		return http.StatusBadRequest
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 400:
+		return apiErr.StatusCode
+	}
+
+	return http.StatusBadGateway
+}
+
+// collection routes path to fetch and makes it available to Preload.
+func (p *proxy) collection(mux *http.ServeMux, path string, fetch fetchFunc) {
+	p.collections[resourceKind(path)] = fetch
+	mux.Handle("GET "+path, p.handle(fetch))
+}
+
+// Preload caches resources concurrently, logging progress as each one is done, and
+// returns the errors of those that failed. Unknown resources are reported without
+// preventing the others from being cached.
+func (p *proxy) Preload(ctx context.Context, resources ...resourceKind) error {
+	type preload struct {
+		resource resourceKind
+		r        *http.Request
+		fetch    fetchFunc
+	}
+
+	// Check every resource before starting any fetch, errs is only shared once the
+	// goroutines run.
+	var (
+		preloads []preload
+		errs     []error
+	)
+	for _, resource := range resources {
+		fetch, ok := p.collections[resource]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%v: unknown collection", resource))
+			continue
+		}
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, string(resource), nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", resource, err))
+			continue
+		}
+		preloads = append(preloads, preload{resource, r, fetch})
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	for _, pl := range preloads {
+		wg.Add(1)
+		go func(pl preload) {
+			defer wg.Done()
+			_, err := p.refresh(pl.r.URL.RequestURI(), pl.r, pl.fetch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%v: %w", pl.resource, err))
+				return
+			}
+			log.Printf("preloaded %v (%v/%v)", pl.resource, done, len(preloads))
+		}(pl)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// handle serves fetch through the cache, keyed by path and query. Entries expired for
+// less than p.stale are served as is while being refreshed in the background, marked
+// with an "X-Cache: stale" header.
+func (p *proxy) handle(fetch fetchFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.RequestURI()
+		now := p.now()
+
+		p.mu.Lock()
+		entry, ok := p.cache[key]
+		stale := ok && now.After(entry.expiresAt) && now.Before(entry.expiresAt.Add(p.stale))
+		revalidate := stale && !entry.refreshing
+		if ok {
+			entry.refreshing = entry.refreshing || revalidate
+			entry.usedAt = now
+			p.cache[key] = entry
+		}
+		p.mu.Unlock()
+
+		switch {
+		case revalidate:
+			go func(r *http.Request) {
+				if _, err := p.refresh(key, r, fetch); err != nil {
+					log.Printf("%v: background refresh: %v", key, err)
+				}
+			}(r.Clone(context.Background()))
+			fallthrough
+		case stale:
+			w.Header().Set("X-Cache", "stale")
+		case !ok || now.After(entry.expiresAt):
+			var err error
+			entry, err = p.refresh(key, r, fetch)
+			if r.Context().Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Printf("%v: %v", key, err)
+				http.Error(w, err.Error(), status(err))
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(entry.body)
+	})
+}
+
+// refreshCall is a refresh in progress, its result is shared by every request waiting
+// on it.
+type refreshCall struct {
+	done  chan struct{}
+	entry cached
+	err   error
+}
+
+// refresh fetches key from 10000ft and caches it, unless a refresh of key is already in
+// progress, in which case it waits for that one. Refreshes failing on the context of
+// their request aren't shared, the waiting requests refreshing again.
+func (p *proxy) refresh(key string, r *http.Request, fetch fetchFunc) (cached, error) {
+	p.mu.Lock()
+	if call, ok := p.refreshes[key]; ok {
+		p.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-r.Context().Done():
+			return cached{}, r.Context().Err()
+		}
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			return p.refresh(key, r, fetch)
+		}
+		return call.entry, call.err
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	p.refreshes[key] = call
+	p.mu.Unlock()
+
+	call.entry, call.err = p.load(key, r, fetch)
+
+	p.mu.Lock()
+	delete(p.refreshes, key)
+	p.mu.Unlock()
+	close(call.done)
+
+	return call.entry, call.err
+}
+
+// load fetches key from 10000ft and caches it. A failed load leaves the cached entry in
+// place, to be retried by the next request.
+func (p *proxy) load(key string, r *http.Request, fetch fetchFunc) (entry cached, err error) {
+	defer func() {
+		if err != nil {
+			p.mu.Lock()
+			if e, ok := p.cache[key]; ok {
+				e.refreshing = false
+				p.cache[key] = e
+			}
+			p.mu.Unlock()
+		}
+	}()
+
+	opts := map[string]string{}
+	for k := range r.URL.Query() {
+		opts[k] = r.URL.Query().Get(k)
+	}
+
+	result, err := fetch(r, opts)
+	if err != nil {
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	now := p.now()
+	entry = cached{body: body, expiresAt: now.Add(p.ttl), usedAt: now}
+	p.mu.Lock()
+	p.cache[key] = entry
+	p.evict(now)
+	p.mu.Unlock()
+
+	return
+}
+
+// evict drops the entries that can't be served anymore, then the least recently used
+// ones while there are more than p.maxEntries. p.mu must be held.
+func (p *proxy) evict(now time.Time) {
+	for key, e := range p.cache {
+		if !e.refreshing && now.After(e.expiresAt.Add(p.stale)) {
+			delete(p.cache, key)
+		}
+	}
+
+	for len(p.cache) > p.maxEntries {
+		var oldest string
+		for key, e := range p.cache {
+			if oldest == "" || e.usedAt.Before(p.cache[oldest].usedAt) {
+				oldest = key
+			}
+		}
+		delete(p.cache, oldest)
+	}
+}
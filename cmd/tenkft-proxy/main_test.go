@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+// newTestProxy returns a proxy without a 10000ft client, routes being served by
+// fetch functions.
+func newTestProxy(t *testing.T) *proxy {
+	t.Helper()
+
+	p := &proxy{
+		ttl:         time.Minute,
+		maxEntries:  100,
+		now:         time.Now,
+		cache:       map[string]cached{},
+		refreshes:   map[string]*refreshCall{},
+		collections: map[resourceKind]fetchFunc{},
+	}
+
+	return p
+}
+
+func TestPreload(t *testing.T) {
+	p := newTestProxy(t)
+
+	var calls int32
+	fetch := func(r *http.Request, opts map[string]string) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return map[string]string{"path": r.URL.Path}, nil
+	}
+	mux := http.NewServeMux()
+	for _, path := range []string{"/users", "/projects", "/roles", "/holidays"} {
+		p.collection(mux, path, fetch)
+	}
+	// A collection whose path can't make a request.
+	p.collections["/bad\x7f"] = fetch
+
+	err := p.Preload(context.Background(), "/users", "/nope", "/projects", "/bad\x7f", "/roles", "/unknown", "/holidays")
+	if err == nil || !strings.Contains(err.Error(), "/nope: unknown collection") || !strings.Contains(err.Error(), "/unknown: unknown collection") || !strings.Contains(err.Error(), "/bad") {
+		t.Errorf("expected the unknown and invalid collections to be reported, got %v", err)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected the 4 valid collections to be fetched, got %v", calls)
+	}
+	for _, key := range []string{"/users", "/projects", "/roles", "/holidays"} {
+		if _, ok := p.cache[key]; !ok {
+			t.Errorf("expected %v to be cached", key)
+		}
+	}
+}
+
+// get serves a GET of target through h.
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return w
+}
+
+func TestHandle(t *testing.T) {
+	p := newTestProxy(t)
+
+	var calls int32
+	h := p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		if opts["fail"] != "" {
+			return nil, errors.New("upstream down")
+		}
+		return map[string]string{"page": opts["page"]}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		w := get(h, "/users?page=2")
+		if w.Code != http.StatusOK || w.Body.String() != `{"page":"2"}` || w.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected response %v %v %v", w.Code, w.Header(), w.Body)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the second request to be served from the cache, got %v calls", calls)
+	}
+
+	get(h, "/users?page=3")
+	if calls != 2 {
+		t.Errorf("expected the query to be part of the cache key, got %v calls", calls)
+	}
+
+	if w := get(h, "/users?fail=1"); w.Code != http.StatusBadGateway || !strings.Contains(w.Body.String(), "upstream down") {
+		t.Errorf("expected a bad gateway, got %v %v", w.Code, w.Body)
+	}
+	if _, ok := p.cache["/users?fail=1"]; ok {
+		t.Error("expected failures not to be cached")
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	p := newTestProxy(t)
+
+	h := p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		switch opts["fail"] {
+		case "id":
+			return pathID(r)
+		case "api":
+			return nil, &tenkft.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+		}
+		return nil, errors.New("upstream down")
+	})
+
+	for target, expected := range map[string]int{
+		"/projects/x?fail=id":  http.StatusBadRequest,
+		"/projects/1?fail=api": http.StatusNotFound,
+		"/projects/1?fail=net": http.StatusBadGateway,
+	} {
+		if w := get(h, target); w.Code != expected {
+			t.Errorf("expected %v for %v, got %v %v", expected, target, w.Code, w.Body)
+		}
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	p := newTestProxy(t)
+	p.maxEntries = 2
+
+	h := p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		return r.URL.Path, nil
+	})
+
+	get(h, "/a")
+	get(h, "/b")
+	get(h, "/a")
+	get(h, "/c")
+	if _, ok := p.cache["/b"]; ok || len(p.cache) != 2 {
+		t.Errorf("expected the least recently used entry to be evicted, got %v entries", len(p.cache))
+	}
+
+	p.maxEntries = 100
+	p.cache["/expired"] = cached{body: []byte(`"expired"`), expiresAt: time.Now().Add(-time.Second)}
+	get(h, "/d")
+	if _, ok := p.cache["/expired"]; ok {
+		t.Error("expected the expired entry to be dropped")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestLimitedTransport(t *testing.T) {
+	var calls int32
+	upstream := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		body := `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": 1, "next": "/projects?page=2"}}`
+		if r.URL.Query().Get("page") == "2" {
+			body = `{"data": [{"id": 3}], "paging": {"page": 2, "next": null}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+	})
+	ticks := make(chan time.Time)
+	c, err := tenkft.NewClient("test", tenkft.Production, tenkft.WithTransport(&limitedTransport{limiter: ticks, next: upstream}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := newTestProxy(t)
+	h := p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		projects, _, err := c.GetAllProjectsCtx(r.Context(), opts)
+		return projects, err
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- get(h, "/projects") }()
+
+	// Every page of the miss waits for its own tick.
+	for page := 0; page < 2; page++ {
+		time.Sleep(20 * time.Millisecond)
+		if n := atomic.LoadInt32(&calls); int(n) != page {
+			t.Fatalf("expected %v upstream calls before tick %v, got %v", page, page+1, n)
+		}
+		ticks <- time.Now()
+	}
+
+	w := <-done
+	if calls != 2 || !strings.Contains(w.Body.String(), `"id":3`) {
+		t.Errorf("expected both pages to be fetched, got %v calls and %v", calls, w.Body)
+	}
+
+	// A hit doesn't reach the transport.
+	if w := get(h, "/projects"); w.Code != http.StatusOK || calls != 2 {
+		t.Errorf("expected a hit to be served from the cache, got %v after %v calls", w.Code, calls)
+	}
+}
+
+func TestRefreshCoalescing(t *testing.T) {
+	p := newTestProxy(t)
+
+	var calls int32
+	release := make(chan struct{})
+	h := p.handle(func(r *http.Request, opts map[string]string) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return r.URL.Path, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if w := get(h, "/users"); w.Body.String() != `"/users"` {
+				t.Errorf("expected the shared response, got %v %v", w.Code, w.Body)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected concurrent misses to share a fetch, got %v fetches", calls)
+	}
+}
+
+// clock is a settable time source.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// upstream serves a version of a resource, increased by every fetch, blocking
+// fetches while held.
+type upstream struct {
+	mu      sync.Mutex
+	version int
+	fail    bool
+	hold    chan struct{}
+}
+
+func (u *upstream) fetch(r *http.Request, opts map[string]string) (interface{}, error) {
+	u.mu.Lock()
+	hold := u.hold
+	u.mu.Unlock()
+	if hold != nil {
+		<-hold
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.fail {
+		return nil, errors.New("upstream down")
+	}
+	u.version++
+
+	return u.version, nil
+}
+
+func (u *upstream) fetches() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.version
+}
+
+// waitFor polls cond until it holds or a second passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	p := newTestProxy(t)
+	p.stale = 10 * time.Minute
+	clk := &clock{now: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	p.now = clk.Now
+
+	up := &upstream{}
+	h := p.handle(up.fetch)
+	cachedBody := func() string {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return string(p.cache["/users"].body)
+	}
+
+	if w := get(h, "/users"); w.Body.String() != "1" || w.Header().Get("X-Cache") != "" {
+		t.Fatalf("expected a fresh response, got %v %v", w.Header(), w.Body)
+	}
+
+	// Expired but within -stale: served at once while a single refresh runs.
+	clk.Advance(2 * time.Minute)
+	up.mu.Lock()
+	up.hold = make(chan struct{})
+	up.mu.Unlock()
+	for i := 0; i < 3; i++ {
+		if w := get(h, "/users"); w.Body.String() != "1" || w.Header().Get("X-Cache") != "stale" {
+			t.Errorf("expected the stale response, got %v %v", w.Header(), w.Body)
+		}
+	}
+	up.mu.Lock()
+	close(up.hold)
+	up.hold = nil
+	up.mu.Unlock()
+
+	waitFor(t, func() bool { return cachedBody() == "2" })
+	if w := get(h, "/users"); w.Body.String() != "2" || w.Header().Get("X-Cache") != "" {
+		t.Errorf("expected the refreshed response, got %v %v", w.Header(), w.Body)
+	}
+	if n := up.fetches(); n != 2 {
+		t.Errorf("expected a single background refresh, got %v fetches", n-1)
+	}
+
+	// A failed background refresh keeps the stale entry and lets the next request retry.
+	clk.Advance(2 * time.Minute)
+	up.mu.Lock()
+	up.fail = true
+	up.mu.Unlock()
+	get(h, "/users")
+	waitFor(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return !p.cache["/users"].refreshing
+	})
+	up.mu.Lock()
+	up.fail = false
+	up.mu.Unlock()
+	if w := get(h, "/users"); w.Body.String() != "2" || w.Header().Get("X-Cache") != "stale" {
+		t.Errorf("expected the stale response after a failed refresh, got %v %v", w.Header(), w.Body)
+	}
+	waitFor(t, func() bool { return cachedBody() == "3" })
+
+	// Past -stale: the request waits for a fresh response.
+	clk.Advance(time.Hour)
+	if w := get(h, "/users"); w.Body.String() != "4" || w.Header().Get("X-Cache") != "" {
+		t.Errorf("expected a fresh response past -stale, got %v %v", w.Header(), w.Body)
+	}
+
+	clk.Advance(time.Hour)
+	up.mu.Lock()
+	up.fail = true
+	up.mu.Unlock()
+	if w := get(h, "/users"); w.Code != http.StatusBadGateway {
+		t.Errorf("expected a bad gateway past -stale with upstream down, got %v %v", w.Code, w.Body)
+	}
+}
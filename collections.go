@@ -0,0 +1,170 @@
+package tenkft
+
+import "encoding/json"
+
+// The collection types decode with a non-nil Data and Paging, even when the JSON has
+// "data": null or no paging, whether they are listed through an Endpoint, decoded with
+// json.Unmarshal or nested in an item, see Project and User.
+
+// nonNil sets data and paging to empty values when nil.
+func nonNil[T any](data *[]T, paging **Paging) {
+	if *data == nil {
+		*data = []T{}
+	}
+	if *paging == nil {
+		*paging = &Paging{}
+	}
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (ps *Projects) UnmarshalJSON(data []byte) error {
+	type projects Projects
+	err := json.Unmarshal(data, (*projects)(ps))
+	nonNil(&ps.Data, &ps.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (ts *Tags) UnmarshalJSON(data []byte) error {
+	type tags Tags
+	err := json.Unmarshal(data, (*tags)(ts))
+	nonNil(&ts.Data, &ts.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (cfvs *CustomFieldValues) UnmarshalJSON(data []byte) error {
+	type customFieldValues CustomFieldValues
+	err := json.Unmarshal(data, (*customFieldValues)(cfvs))
+	nonNil(&cfvs.Data, &cfvs.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (a *Availabilities) UnmarshalJSON(data []byte) error {
+	type availabilities Availabilities
+	err := json.Unmarshal(data, (*availabilities)(a))
+	nonNil(&a.Data, &a.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (users *Users) UnmarshalJSON(data []byte) error {
+	type userList Users
+	err := json.Unmarshal(data, (*userList)(users))
+	nonNil(&users.Data, &users.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (as *Assignments) UnmarshalJSON(data []byte) error {
+	type assignments Assignments
+	err := json.Unmarshal(data, (*assignments)(as))
+	nonNil(&as.Data, &as.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (p *Phases) UnmarshalJSON(data []byte) error {
+	type phases Phases
+	err := json.Unmarshal(data, (*phases)(p))
+	nonNil(&p.Data, &p.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (p *PlaceholderResources) UnmarshalJSON(data []byte) error {
+	type placeholderResources PlaceholderResources
+	err := json.Unmarshal(data, (*placeholderResources)(p))
+	nonNil(&p.Data, &p.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (lts *LeaveTypes) UnmarshalJSON(data []byte) error {
+	type leaveTypes LeaveTypes
+	err := json.Unmarshal(data, (*leaveTypes)(lts))
+	nonNil(&lts.Data, &lts.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (rs *Roles) UnmarshalJSON(data []byte) error {
+	type roles Roles
+	err := json.Unmarshal(data, (*roles)(rs))
+	nonNil(&rs.Data, &rs.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (b *BillRates) UnmarshalJSON(data []byte) error {
+	type billRates BillRates
+	err := json.Unmarshal(data, (*billRates)(b))
+	nonNil(&b.Data, &b.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (t *TimeEntries) UnmarshalJSON(data []byte) error {
+	type timeEntries TimeEntries
+	err := json.Unmarshal(data, (*timeEntries)(t))
+	nonNil(&t.Data, &t.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (b *BudgetItems) UnmarshalJSON(data []byte) error {
+	type budgetItems BudgetItems
+	err := json.Unmarshal(data, (*budgetItems)(b))
+	nonNil(&b.Data, &b.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (e *ExpenseItems) UnmarshalJSON(data []byte) error {
+	type expenseItems ExpenseItems
+	err := json.Unmarshal(data, (*expenseItems)(e))
+	nonNil(&e.Data, &e.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (h *Holidays) UnmarshalJSON(data []byte) error {
+	type holidays Holidays
+	err := json.Unmarshal(data, (*holidays)(h))
+	nonNil(&h.Data, &h.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (a *Approvals) UnmarshalJSON(data []byte) error {
+	type approvals Approvals
+	err := json.Unmarshal(data, (*approvals)(a))
+	nonNil(&a.Data, &a.Paging)
+
+	return err
+}
+
+// UnmarshalJSON decodes data leaving a non-nil Data and Paging.
+func (d *Disciplines) UnmarshalJSON(data []byte) error {
+	type disciplines Disciplines
+	err := json.Unmarshal(data, (*disciplines)(d))
+	nonNil(&d.Data, &d.Paging)
+
+	return err
+}
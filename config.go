@@ -0,0 +1,192 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Profile is a named client configuration, e.g. "prod-us" or "staging", so that tools
+// share their configuration instead of each parsing its own. Profiles are read from a
+// JSON file keyed by profile name:
+//
+//	{
+//		"prod-us": {"env": "rm", "max_retries": 3, "location": "America/New_York"},
+//		"staging": {"env": "staging", "auth": "bearer"}
+//	}
+//
+// YAML isn't supported as it would take a dependency outside the standard library,
+// which the package does without. Tokens are best left out of the file, in TENKFT_TOKEN
+// or the credentials file, see SaveToken.
+type Profile struct {
+	Token string `json:"token,omitempty"`
+	// Env is an environment URL or one of the names production, staging, rm and rm-eu.
+	Env        string     `json:"env"`
+	APIVersion APIVersion `json:"api_version,omitempty"`
+	// Auth is "bearer" for BearerAuth, otherwise the header the token is sent in.
+	Auth       string `json:"auth,omitempty"`
+	MaxRetries int    `json:"max_retries,omitempty"`
+	// Location is the account time zone, e.g. "Europe/London".
+	Location string `json:"location,omitempty"`
+}
+
+// envNames are the names a Profile may use for its Env.
+var envNames = map[string]string{
+	"production": Production,
+	"staging":    Staging,
+	"rm":         ResourceManagement,
+	"rm-eu":      ResourceManagementEU,
+}
+
+// Environment variables read by NewClientFromEnv. TENKFT_TOKEN, TENKFT_ENV,
+// TENKFT_API_VERSION, TENKFT_AUTH and TENKFT_MAX_RETRIES override the fields of the
+// profile.
+const (
+	// ConfigEnvVar holds the path of the profiles file, ProfilesPath by default.
+	ConfigEnvVar = "TENKFT_CONFIG"
+	// ProfileEnvVar holds the name of the profile to use.
+	ProfileEnvVar = "TENKFT_PROFILE"
+)
+
+// ProfilesPath returns the default location of the profiles file,
+// tenkft/profiles.json in the user's configuration directory.
+func ProfilesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tenkft", "profiles.json"), nil
+}
+
+// LoadProfiles reads the JSON profiles file at path.
+func LoadProfiles(path string) (profiles map[string]Profile, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(b, &profiles); err != nil {
+		err = fmt.Errorf("%v: %w", path, err)
+	}
+
+	return
+}
+
+// ProfileFromEnv returns the profile named by TENKFT_PROFILE, read from the profiles
+// file, with the fields set in the environment overriding it. Without TENKFT_PROFILE
+// the profile is built from the environment alone. When no token is configured the one
+// stored for the profile, or DefaultProfile, in the credentials file is used.
+func ProfileFromEnv() (p Profile, err error) {
+	name := os.Getenv(ProfileEnvVar)
+	if name != "" {
+		path := os.Getenv(ConfigEnvVar)
+		if path == "" {
+			if path, err = ProfilesPath(); err != nil {
+				return
+			}
+		}
+
+		var profiles map[string]Profile
+		if profiles, err = LoadProfiles(path); err != nil {
+			return p, fmt.Errorf("profile %v: %w", name, err)
+		}
+
+		var ok bool
+		if p, ok = profiles[name]; !ok {
+			return p, fmt.Errorf("profile %v not found in %v", name, path)
+		}
+	}
+
+	for key, field := range map[string]*string{
+		"TENKFT_TOKEN":       &p.Token,
+		"TENKFT_ENV":         &p.Env,
+		"TENKFT_API_VERSION": (*string)(&p.APIVersion),
+		"TENKFT_AUTH":        &p.Auth,
+	} {
+		if v := os.Getenv(key); v != "" {
+			*field = v
+		}
+	}
+
+	if v := os.Getenv("TENKFT_MAX_RETRIES"); v != "" {
+		if p.MaxRetries, err = strconv.Atoi(v); err != nil {
+			return p, fmt.Errorf("TENKFT_MAX_RETRIES: %w", err)
+		}
+	}
+
+	if p.Token == "" {
+		if name == "" {
+			name = DefaultProfile
+		}
+		p.Token, err = LoadToken(name)
+	}
+
+	return
+}
+
+// EnvURL returns the environment URL of p, Production when Env is empty.
+func (p Profile) EnvURL() string {
+	if p.Env == "" {
+		return Production
+	}
+	if url, ok := envNames[p.Env]; ok {
+		return url
+	}
+
+	return p.Env
+}
+
+// Options returns the ClientOptions configured by p, the token and environment being
+// passed to NewClient separately.
+func (p Profile) Options() (opts []ClientOption, err error) {
+	if p.APIVersion != "" {
+		opts = append(opts, WithAPIVersion(p.APIVersion))
+	}
+
+	switch p.Auth {
+	case "":
+	case "bearer":
+		opts = append(opts, WithAuth(BearerAuth()))
+	default:
+		opts = append(opts, WithAuthHeader(p.Auth))
+	}
+
+	if p.MaxRetries != 0 {
+		opts = append(opts, WithMaxRetries(p.MaxRetries))
+	}
+
+	if p.Location != "" {
+		loc, err := time.LoadLocation(p.Location)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithLocation(loc))
+	}
+
+	return
+}
+
+// NewClient returns a client configured by p, opts being applied after its own.
+func (p Profile) NewClient(opts ...ClientOption) (*Client, error) {
+	profileOpts, err := p.Options()
+	if err != nil {
+		return &Client{}, err
+	}
+
+	return NewClient(p.Token, p.EnvURL(), append(profileOpts, opts...)...)
+}
+
+// NewClientFromEnv returns a client configured by ProfileFromEnv, opts being applied
+// after the profile's.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	p, err := ProfileFromEnv()
+	if err != nil {
+		return &Client{}, err
+	}
+
+	return p.NewClient(opts...)
+}
@@ -0,0 +1,32 @@
+package tenkft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	profiles := `{"prod-us": {"env": "rm", "max_retries": 3, "auth": "bearer"}, "staging": {"env": "staging"}}`
+	if err := os.WriteFile(path, []byte(profiles), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnvVar, path)
+	t.Setenv(ProfileEnvVar, "prod-us")
+	t.Setenv("TENKFT_TOKEN", "secret")
+	t.Setenv("TENKFT_MAX_RETRIES", "5")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal("could not create client", err)
+	}
+	if client.Env() != ResourceManagement || client.MaxRetries != 5 || client.headers()["Authorization"] != "Bearer secret" {
+		t.Errorf("expected the prod-us profile with the environment overrides, got %v, %v retries, %v", client.Env(), client.MaxRetries, client.headers())
+	}
+
+	t.Setenv(ProfileEnvVar, "prod-eu")
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Error("expected an unknown profile to fail")
+	}
+}
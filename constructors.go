@@ -1,5 +1,10 @@
 package tenkft
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // all constructors are here.
 
 // NewProjects - initializes a Projects struct with non nil fields.
@@ -9,7 +14,7 @@ func NewProjects() *Projects {
 
 // NewProject - initializes a Project struct with non nil fields.
 func NewProject() *Project {
-	return &Project{baseProject: &baseProject{}}
+	return &Project{baseProject: &baseProject{}, Tags: *NewTags(), Assignments: *NewAssignments()}
 }
 
 // NewUsers - initializes a Users struct with non nil fields.
@@ -19,5 +24,188 @@ func NewUsers() *Users {
 
 // NewUser - initializes a User struct with non nil fields.
 func NewUser() *User {
-	return &User{baseUser: &baseUser{}}
+	return &User{
+		baseUser:       &baseUser{},
+		Tags:           *NewTags(),
+		Assignments:    *NewAssignments(),
+		Availabilities: *NewAvailabilities(),
+	}
+}
+
+// NewAssignments - initializes an Assignments struct with non nil fields.
+func NewAssignments() *Assignments {
+	return &Assignments{Paging: &Paging{}, Data: []*Assignment{}}
+}
+
+// NewAssignment - initializes an Assignment struct with non nil fields.
+func NewAssignment() *Assignment {
+	return &Assignment{baseAssignment: &baseAssignment{}}
+}
+
+// NewAssignmentPercent - initializes an assignment of userID to assignableID from from to
+// to inclusive, for pct of their working hours, 1 being full time.
+func NewAssignmentPercent(userID UserID, assignableID AssignableID, from, to time.Time, pct float64) *Assignment {
+	a := newAllocation(userID, assignableID, from, to, AllocationPercent)
+	a.Percent = pct
+
+	return a
+}
+
+// NewAssignmentFixedHours - initializes an assignment of userID to assignableID for hours
+// in total, spread from from to to inclusive.
+func NewAssignmentFixedHours(userID UserID, assignableID AssignableID, from, to time.Time, hours float64) *Assignment {
+	a := newAllocation(userID, assignableID, from, to, AllocationFixed)
+	a.FixedHours = hours
+
+	return a
+}
+
+// NewAssignmentHoursPerDay - initializes an assignment of userID to assignableID for
+// hours every working day from from to to inclusive.
+func NewAssignmentHoursPerDay(userID UserID, assignableID AssignableID, from, to time.Time, hours float64) *Assignment {
+	a := newAllocation(userID, assignableID, from, to, AllocationHoursPerDay)
+	a.HoursPerDay = hours
+
+	return a
+}
+
+// newAllocation returns an assignment in mode with no quantity set, the API silently
+// picks one when a payload holds several.
+func newAllocation(userID UserID, assignableID AssignableID, from, to time.Time, mode string) *Assignment {
+	return &Assignment{
+		baseAssignment: &baseAssignment{
+			AllocationMode: mode,
+			AssignableID:   assignableID,
+			StartsAt:       from.Format(DateFormat),
+			EndsAt:         to.Format(DateFormat),
+		},
+		UserID: userID,
+	}
+}
+
+// NewPhases - initializes a Phases struct with non nil fields.
+func NewPhases() *Phases {
+	return &Phases{Paging: &Paging{}, Data: []*Phase{}}
+}
+
+// NewPhase - initializes a Phase struct with non nil fields.
+func NewPhase() *Phase {
+	return &Phase{basePhase: &basePhase{}}
+}
+
+// NewTags - initializes a Tags struct with non nil fields.
+func NewTags() *Tags {
+	return &Tags{Paging: &Paging{}, Data: []*Tag{}}
+}
+
+// NewTag - initializes a Tag struct with value.
+func NewTag(value string) *Tag {
+	return &Tag{baseTag: &baseTag{Value: value}}
+}
+
+// NewAvailabilities - initializes an Availabilities struct with non nil fields.
+func NewAvailabilities() *Availabilities {
+	return &Availabilities{Paging: &Paging{}, Data: []*Availability{}}
+}
+
+// NewLeaveTypes - initializes a LeaveTypes struct with non nil fields.
+func NewLeaveTypes() *LeaveTypes {
+	return &LeaveTypes{Paging: &Paging{}, Data: []*LeaveType{}}
+}
+
+// NewRoles - initializes a Roles struct with non nil fields.
+func NewRoles() *Roles {
+	return &Roles{Paging: &Paging{}, Data: []*Role{}}
+}
+
+// NewBillRates - initializes a BillRates struct with non nil fields.
+func NewBillRates() *BillRates {
+	return &BillRates{Paging: &Paging{}, Data: []*BillRate{}}
+}
+
+// NewTimeEntries - initializes a TimeEntries struct with non nil fields.
+func NewTimeEntries() *TimeEntries {
+	return &TimeEntries{Paging: &Paging{}, Data: []*TimeEntry{}}
+}
+
+// NewHolidays - initializes a Holidays struct with non nil fields.
+func NewHolidays() *Holidays {
+	return &Holidays{Paging: &Paging{}, Data: []*Holiday{}}
+}
+
+// NewApprovals - initializes an Approvals struct with non nil fields.
+func NewApprovals() *Approvals {
+	return &Approvals{Paging: &Paging{}, Data: []*Approval{}}
+}
+
+// NewDisciplines - initializes a Disciplines struct with non nil fields.
+func NewDisciplines() *Disciplines {
+	return &Disciplines{Paging: &Paging{}, Data: []*Discipline{}}
+}
+
+// NewBudgetItems - initializes a BudgetItems struct with non nil fields.
+func NewBudgetItems() *BudgetItems {
+	return &BudgetItems{Paging: &Paging{}, Data: []*BudgetItem{}}
+}
+
+// NewBudgetItem - initializes a BudgetItem of itemType, BudgetTimeFees or BudgetExpenses, for amount.
+func NewBudgetItem(itemType string, amount float64) *BudgetItem {
+	return &BudgetItem{baseBudgetItem: &baseBudgetItem{ItemType: itemType, Amount: amount}}
+}
+
+// NewExpenseItems - initializes an ExpenseItems struct with non nil fields.
+func NewExpenseItems() *ExpenseItems {
+	return &ExpenseItems{Paging: &Paging{}, Data: []*ExpenseItem{}}
+}
+
+// NewExpenseItem - initializes an expense of amount incurred by userID on assignableID on date.
+func NewExpenseItem(userID UserID, assignableID AssignableID, date time.Time, amount float64) *ExpenseItem {
+	return &ExpenseItem{
+		baseExpenseItem: &baseExpenseItem{AssignableID: assignableID, Date: date.Format(DateFormat), Amount: amount},
+		UserID:          userID,
+	}
+}
+
+// NewSettings - initializes Settings holding v encoded as JSON, e.g. to create a project with given settings.
+func NewSettings(v interface{}) (s Settings, err error) {
+	s.raw, err = json.Marshal(v)
+	return
+}
+
+// NewProjectCodes - initializes ProjectCodes generating codes from pattern, e.g. "%s-%04d", for projects created through c.
+func NewProjectCodes(c *Client, pattern string) *ProjectCodes {
+	return &ProjectCodes{Pattern: pattern, c: c}
+}
+
+// NewRefData - initializes a RefData that loads through c and keeps each kind of data for ttl.
+func NewRefData(c *Client, ttl time.Duration) *RefData {
+	return &RefData{TTL: ttl, c: c}
+}
+
+// NewResolver - initializes a Resolver that loads through c.
+func NewResolver(c *Client) *Resolver {
+	return &Resolver{c: c}
+}
+
+// NewCalendar - initializes a Calendar in loc with the given holidays, which may be nil.
+func NewCalendar(loc *time.Location, holidays *Holidays) *Calendar {
+	cal := &Calendar{
+		DefaultHours: DefaultWorkingHours,
+		loc:          loc,
+		holidays:     map[string]string{},
+		blackouts:    map[string]bool{},
+	}
+
+	if holidays != nil {
+		for _, h := range holidays.Data {
+			cal.holidays[h.Date] = h.Name
+		}
+	}
+
+	return cal
+}
+
+// NewAssetCache - initializes an AssetCache that refreshes projects through c and keeps assets for ttl.
+func NewAssetCache(c *Client, ttl time.Duration) *AssetCache {
+	return &AssetCache{TTL: ttl, c: c, assets: map[string]*Asset{}, flights: &flightGroup{calls: map[string]*flight{}}}
 }
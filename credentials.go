@@ -0,0 +1,107 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultProfile is the profile name tokens are stored under when TENKFT_PROFILE isn't
+// set.
+const DefaultProfile = "default"
+
+// CredentialsPath returns the location of the credentials file,
+// tenkft/credentials.json in the user's configuration directory. It maps profile
+// names to tokens and is only readable by the user, so that tools don't need the token
+// on their command line where it leaks into shell history.
+func CredentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tenkft", "credentials.json"), nil
+}
+
+// LoadToken returns the token stored for profile, "" if there is none. The file is
+// rejected when other users may read it.
+func LoadToken(profile string) (token string, err error) {
+	tokens, path, err := loadTokens()
+	if err != nil {
+		return
+	}
+
+	token = tokens[profile]
+	if token == "" {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("%v: permissions %v are too open, expected 0600", path, info.Mode().Perm())
+	}
+
+	return
+}
+
+// SaveToken stores token for profile in the credentials file, creating it with 0600
+// permissions. An empty token removes the profile's.
+func SaveToken(profile, token string) error {
+	tokens, path, err := loadTokens()
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		delete(tokens, profile)
+	} else {
+		tokens[profile] = token
+	}
+
+	b, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// Write a new file and rename it so that the permissions hold even if the file
+	// existed with broader ones.
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// loadTokens reads the credentials file, a missing file holding no tokens.
+func loadTokens() (tokens map[string]string, path string, err error) {
+	if path, err = CredentialsPath(); err != nil {
+		return
+	}
+
+	tokens = map[string]string{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, path, nil
+	}
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(b, &tokens); err != nil {
+		err = fmt.Errorf("%v: %w", path, err)
+	}
+
+	return
+}
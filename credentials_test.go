@@ -0,0 +1,36 @@
+package tenkft
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCredentials(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv("TENKFT_TOKEN", "")
+
+	if err := SaveToken(DefaultProfile, "secret"); err != nil {
+		t.Fatal("could not save token", err)
+	}
+
+	p, err := ProfileFromEnv()
+	if err != nil || p.Token != "secret" {
+		t.Errorf("expected the stored token, got %q, %v", p.Token, err)
+	}
+
+	path, _ := CredentialsPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal("could not stat credentials", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the credentials file to be private, got %v", info.Mode())
+	}
+
+	os.Chmod(path, 0644)
+	if _, err := LoadToken(DefaultProfile); err == nil {
+		t.Error("expected a readable credentials file to be rejected")
+	}
+}
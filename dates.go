@@ -0,0 +1,60 @@
+package tenkft
+
+import "time"
+
+// DateFormat is the layout of the dates the API sends and expects, e.g. starts_at.
+const DateFormat = "2006-01-02"
+
+// Location returns the account time zone used by the date helpers, UTC unless
+// WithLocation was passed to the constructor.
+func (c *Client) Location() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+
+	return c.location
+}
+
+// WeekStart returns the first day of the week used by the date helpers, Monday unless
+// WithWeekStart was passed to the constructor.
+func (c *Client) WeekStart() time.Weekday {
+	return c.weekStart
+}
+
+// Today returns midnight of the current day in the account time zone.
+func (c *Client) Today() time.Time {
+	return c.Day(time.Now())
+}
+
+// Day returns midnight of the day t falls on in the account time zone.
+func (c *Client) Day(t time.Time) time.Time {
+	t = t.In(c.Location())
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location())
+}
+
+// Week returns the first and last day of the week t falls on, in the account time zone
+// and according to WeekStart.
+func (c *Client) Week(t time.Time) (from, to time.Time) {
+	day := c.Day(t)
+	offset := (int(day.Weekday()) - int(c.weekStart) + 7) % 7
+	from = day.AddDate(0, 0, -offset)
+	to = from.AddDate(0, 0, 6)
+
+	return
+}
+
+// ThisWeek returns the first and last day of the current week.
+func (c *Client) ThisWeek() (from, to time.Time) {
+	return c.Week(time.Now())
+}
+
+// ParseDate parses an API date as midnight in the account time zone.
+func (c *Client) ParseDate(date string) (time.Time, error) {
+	return time.ParseInLocation(DateFormat, date, c.Location())
+}
+
+// FormatDate formats t as an API date, after converting it to the account time zone.
+func (c *Client) FormatDate(t time.Time) string {
+	return t.In(c.Location()).Format(DateFormat)
+}
@@ -0,0 +1,26 @@
+package tenkft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeek(t *testing.T) {
+	loc := time.FixedZone("UTC-8", -8*60*60)
+	client, err := NewClient("", Staging, WithLocation(loc), WithWeekStart(time.Sunday))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday 03:00 UTC is still Sunday evening in UTC-8.
+	from, to := client.Week(time.Date(2018, 3, 12, 3, 0, 0, 0, time.UTC))
+	if client.FormatDate(from) != "2018-03-11" || client.FormatDate(to) != "2018-03-17" {
+		t.Errorf("expected week 2018-03-11 to 2018-03-17, got %v to %v", client.FormatDate(from), client.FormatDate(to))
+	}
+
+	client, _ = NewClient("", Staging)
+	from, _ = client.Week(time.Date(2018, 3, 12, 3, 0, 0, 0, time.UTC))
+	if client.FormatDate(from) != "2018-03-12" {
+		t.Errorf("expected the default week to start on Monday 2018-03-12, got %v", client.FormatDate(from))
+	}
+}
@@ -0,0 +1,629 @@
+package tenkft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// Endpoint describes a resource of the API so that fetching, retries and pagination are
+// shared by every resource. L is the collection type returned by list calls, e.g.
+// *Projects, and T the item type it holds, e.g. *Project. T must be a pointer type.
+// The collections returned always have a non-nil Data and Paging, even when the response
+// has "data": null or no paging, so they can be ranged over and encoded as [].
+// Endpoints the package doesn't wrap yet can be declared by callers:
+//
+//	var customFields = tenkft.Endpoint[*CustomFields, *CustomField]{
+//		Path: "/custom_fields",
+//		Wrap: func(data []*CustomField, paging *tenkft.Paging) *CustomFields {
+//			return &CustomFields{Data: data, Paging: paging}
+//		},
+//	}
+//
+//	fields, _, err := customFields.ListAll(ctx, c, map[string]string{})
+type Endpoint[L any, T any] struct {
+	// Path is appended to the environment URL. It may contain %d verbs, filled in
+	// order by the parentIDs passed to the methods, e.g. "/projects/%d/phases".
+	Path string
+	// Wrap builds the collection returned by List and ListAll from a page of items.
+	Wrap func(data []T, paging *Paging) L
+	// PerPage is the page size requested by ListAll, 50 when zero. It is capped to the
+	// largest page the client's APIVersion serves.
+	PerPage int
+}
+
+// page is the envelope of every list response.
+type page[T any] struct {
+	Data   []T     `json:"data"`
+	Paging *Paging `json:"paging"`
+}
+
+// List fetches a single page of the collection, opts are sent as query parameters.
+func (e Endpoint[L, T]) List(ctx context.Context, c *Client, opts map[string]string, parentIDs ...int) (list L, resp *http.Response, err error) {
+	pg, resp, err := e.list(ctx, c, opts, parentIDs)
+	list = e.Wrap(pg.Data, pg.Paging)
+
+	return
+}
+
+// maxListPages is the number of pages after which ListAll gives up, a last resort
+// against paging that never ends.
+const maxListPages = 100000
+
+// PaginationLoopError is returned by ListAll, and so by the GetAll* methods, when the API
+// points to a page it already served, or keeps paginating past maxListPages, instead of
+// looping forever.
+type PaginationLoopError struct {
+	// URL is the URL of the collection paginated.
+	URL string
+	// Page is the page the API pointed to again, 0 when the page limit was hit.
+	Page int
+	// Pages is the number of distinct pages served.
+	Pages int
+}
+
+func (e *PaginationLoopError) Error() string {
+	if e.Page == 0 {
+		return fmt.Sprintf("tenkft: %v: gave up paginating after %v pages", e.URL, e.Pages)
+	}
+
+	return fmt.Sprintf("tenkft: %v: page %v served again after %v pages", e.URL, e.Page, e.Pages)
+}
+
+// pageObserverKey is the context key the function set by WithPageObserver is stored
+// under.
+type pageObserverKey struct{}
+
+// WithPageObserver returns a copy of ctx making the GetAll*Ctx and StreamAll* methods
+// called with it pass the paging and number of records of every page they fetch to
+// observe, e.g. to record what an export was built from.
+func WithPageObserver(ctx context.Context, observe func(paging *Paging, records int)) context.Context {
+	return context.WithValue(ctx, pageObserverKey{}, observe)
+}
+
+// ListAll fetches every page of the collection and returns the accumulated items.
+// A page failing with a 5xx status is retried per Client.PageRetries. resp and err
+// correspond to the latest page fetched, on error the pages fetched so far are returned.
+// When ctx is canceled err is ctx.Err(), so callers can tell an abort from a failure.
+// A *PaginationLoopError is returned when the API paginates in circles, and an error
+// wrapping ErrLimitExceeded when the collection goes past the Client.Limits of ctx.
+func (e Endpoint[L, T]) ListAll(ctx context.Context, c *Client, opts map[string]string, parentIDs ...int) (list L, resp *http.Response, err error) {
+	all := &page[T]{Data: []T{}, Paging: &Paging{}}
+	resp, err = e.eachPage(ctx, c, opts, parentIDs, func(pg *page[T]) error {
+		all.Paging = pg.Paging
+		all.Data = append(all.Data, pg.Data...)
+		return nil
+	})
+
+	list = e.Wrap(all.Data, all.Paging)
+
+	return
+}
+
+// Stream fetches the collection like ListAll but sends the items on the returned
+// channel as each page arrives, so that they can be processed while later pages are
+// downloading. items is closed once the collection is exhausted or an error stopped
+// it, errs then receives that error, if any, and is closed. Canceling ctx stops the
+// stream with ctx.Err().
+func (e Endpoint[L, T]) Stream(ctx context.Context, c *Client, opts map[string]string, parentIDs ...int) (items <-chan T, errs <-chan error) {
+	return e.stream(ctx, c, opts, parentIDs, nil)
+}
+
+// stream is Stream calling prepare, when set, with the items of every page before they
+// are sent, e.g. to enrich them.
+func (e Endpoint[L, T]) stream(ctx context.Context, c *Client, opts map[string]string, parentIDs []int, prepare func([]T) error) (<-chan T, <-chan error) {
+	items, errs := make(chan T), make(chan error, 1)
+	go func() {
+		defer close(errs)
+
+		_, err := e.eachPage(ctx, c, opts, parentIDs, func(pg *page[T]) error {
+			if prepare != nil {
+				if err := prepare(pg.Data); err != nil {
+					return err
+				}
+			}
+
+			for _, item := range pg.Data {
+				select {
+				case items <- item:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return nil
+		})
+		close(items)
+
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return items, errs
+}
+
+// eachPage fetches every page of the collection, as described by ListAll, calling
+// visit with each of them. An error returned by visit stops the pagination and is
+// returned.
+func (e Endpoint[L, T]) eachPage(ctx context.Context, c *Client, opts map[string]string, parentIDs []int, visit func(pg *page[T]) error) (resp *http.Response, err error) {
+	query := map[string]string{}
+	for k, v := range opts {
+		query[k] = v
+	}
+	query["per_page"] = strconv.Itoa(e.perPage(c))
+
+	var sizer *pageSizer
+	if c.adaptive != nil {
+		sizer = newPageSizer(*c.adaptive, e.perPage(c), query)
+	}
+
+	served, selves := map[int]bool{}, map[string]bool{}
+	limits, pages, fetched := c.Limits(ctx), 0, 0
+	observe, _ := ctx.Value(pageObserverKey{}).(func(*Paging, int))
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var pg *page[T]
+		resized := false
+		resp, err = c.retryPage(ctx, func() (resp *http.Response, err error) {
+			if sizer == nil {
+				pg, resp, err = e.list(ctx, c, query, parentIDs)
+				return
+			}
+
+			sizer.apply(query, fetched)
+			start := time.Now()
+			pg, resp, err = e.list(ctx, c, query, parentIDs)
+			next := fetched
+			if err == nil {
+				next += len(pg.Data)
+			}
+			resized = sizer.observe(time.Since(start), resp, next) || resized
+			return
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return
+		}
+
+		pages++
+		fetched += len(pg.Data)
+		if c.OnProgress != nil {
+			c.OnProgress(fetched, 0, pg.Paging.Page)
+		}
+
+		if limits.MaxRecords > 0 && fetched > limits.MaxRecords {
+			pg.Data = pg.Data[:len(pg.Data)-(fetched-limits.MaxRecords)]
+			err = fmt.Errorf("%v: more than %v records: %w", e.url(c, parentIDs), limits.MaxRecords, ErrLimitExceeded)
+		}
+		if observe != nil {
+			observe(pg.Paging, len(pg.Data))
+		}
+
+		if visitErr := visit(pg); visitErr != nil {
+			return resp, visitErr
+		}
+		if err != nil || !pg.Paging.HasNext() {
+			return
+		}
+
+		if limits.MaxPages > 0 && pages >= limits.MaxPages {
+			err = fmt.Errorf("%v: more than %v pages: %w", e.url(c, parentIDs), limits.MaxPages, ErrLimitExceeded)
+			return
+		}
+
+		loop := circling(pg.Paging, served, selves)
+		if loop == nil && len(served) >= maxListPages {
+			loop = &PaginationLoopError{}
+		}
+		if loop != nil {
+			loop.URL, loop.Pages = e.url(c, parentIDs), len(served)
+			return resp, loop
+		}
+		if resized {
+			// Page numbers change with the page size.
+			served, selves = map[int]bool{}, map[string]bool{}
+		}
+		// Follow the filters of the Next URL, the page number being checked above.
+		next, _ := pg.Paging.NextParams()
+		for k, v := range next {
+			query[k] = v
+		}
+		query["page"] = strconv.Itoa(pg.Paging.GetNextPage())
+	}
+}
+
+// circling records the page p describes in served and selves, the page numbers and Self
+// URLs served so far, and returns a *PaginationLoopError when p was served already or
+// points to a page that was.
+func circling(p *Paging, served map[int]bool, selves map[string]bool) *PaginationLoopError {
+	if served[p.Page] || (p.Self != "" && selves[p.Self]) {
+		return &PaginationLoopError{Page: p.Page}
+	}
+	selves[p.Self] = true
+	served[p.Page] = true
+
+	if next := p.GetNextPage(); served[next] {
+		return &PaginationLoopError{Page: next}
+	}
+
+	return nil
+}
+
+// Count returns the number of items in the collection without downloading it. Pages of
+// a single item are probed, doubling the page number until one comes back empty and then
+// bisecting, so that it takes about 2*log2(n) requests.
+func (e Endpoint[L, T]) Count(ctx context.Context, c *Client, opts map[string]string, parentIDs ...int) (n int, resp *http.Response, err error) {
+	query := map[string]string{}
+	for k, v := range opts {
+		query[k] = v
+	}
+	query["per_page"] = "1"
+
+	done := false
+	probe := func(pageNum int) (found bool) {
+		var pg *page[T]
+		query["page"] = strconv.Itoa(pageNum)
+		resp, err = c.retryPage(ctx, func() (resp *http.Response, err error) {
+			pg, resp, err = e.list(ctx, c, query, parentIDs)
+			return
+		})
+		if err != nil {
+			return
+		}
+
+		found = len(pg.Data) > 0
+		done = found && !pg.Paging.HasNext()
+
+		return
+	}
+
+	// low is the last page known to exist, high the first one past it once probed.
+	low, high := 0, 1
+	for err == nil && !done {
+		if !probe(high) {
+			break
+		}
+		low, high = high, high*2
+	}
+
+	for err == nil && !done && high-low > 1 {
+		mid := low + (high-low)/2
+		if probe(mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	n = low
+
+	return
+}
+
+// Get fetches the item with the given id.
+func (e Endpoint[L, T]) Get(ctx context.Context, c *Client, id int, opts map[string]string, parentIDs ...int) (item T, resp *http.Response, err error) {
+	url := e.url(c, parentIDs) + "/" + strconv.Itoa(id) + "?" + queryfy(opts)
+	resp, err = c.do(ctx, http.MethodGet, url, nil, &item)
+
+	return
+}
+
+// Create POSTs body, marshalled as JSON, and decodes the created item into out.
+func (e Endpoint[L, T]) Create(ctx context.Context, c *Client, body interface{}, out T, parentIDs ...int) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, e.url(c, parentIDs), body, out)
+}
+
+// Update PUTs body, marshalled as JSON, to the item with the given id and decodes the
+// updated item into out.
+func (e Endpoint[L, T]) Update(ctx context.Context, c *Client, id int, body interface{}, out T, parentIDs ...int) (*http.Response, error) {
+	return c.do(ctx, http.MethodPut, e.url(c, parentIDs)+"/"+strconv.Itoa(id), body, out)
+}
+
+// Delete DELETEs the item with the given id.
+func (e Endpoint[L, T]) Delete(ctx context.Context, c *Client, id int, parentIDs ...int) (*http.Response, error) {
+	return c.do(ctx, http.MethodDelete, e.url(c, parentIDs)+"/"+strconv.Itoa(id), nil, nil)
+}
+
+// Next fetches the page following the one p describes, requesting the Next URL exactly as
+// the API returned it. err is ErrNoNextPage when p is the last page.
+func (e Endpoint[L, T]) Next(ctx context.Context, c *Client, p *Paging) (list L, resp *http.Response, err error) {
+	pg := &page[T]{Data: []T{}, Paging: &Paging{}}
+	if !p.HasNext() {
+		err = ErrNoNextPage
+	} else {
+		var next string
+		if next, err = c.resolve(p.Next); err == nil {
+			pg, resp, err = e.fetchPage(ctx, c, next)
+		}
+	}
+
+	list = e.Wrap(pg.Data, pg.Paging)
+
+	return
+}
+
+func (e Endpoint[L, T]) list(ctx context.Context, c *Client, opts map[string]string, parentIDs []int) (pg *page[T], resp *http.Response, err error) {
+	return e.fetchPage(ctx, c, e.url(c, parentIDs)+"?"+queryfy(opts))
+}
+
+// fetchPage GETs the page at url.
+func (e Endpoint[L, T]) fetchPage(ctx context.Context, c *Client, url string) (pg *page[T], resp *http.Response, err error) {
+	pg = &page[T]{Data: []T{}, Paging: &Paging{}}
+	resp, err = c.do(ctx, http.MethodGet, url, nil, pg)
+	if pg.Data == nil {
+		pg.Data = []T{}
+	}
+	if pg.Paging == nil {
+		pg.Paging = &Paging{}
+	}
+	if err == nil {
+		pg.Paging.complete(len(pg.Data))
+	}
+
+	return
+}
+
+func (e Endpoint[L, T]) url(c *Client, parentIDs []int) string {
+	if len(parentIDs) == 0 {
+		return c.env + e.Path
+	}
+
+	args := make([]interface{}, len(parentIDs))
+	for i, id := range parentIDs {
+		args[i] = id
+	}
+
+	return c.env + fmt.Sprintf(e.Path, args...)
+}
+
+func (e Endpoint[L, T]) perPage(c *Client) int {
+	if e.PerPage == 0 {
+		return 50
+	}
+
+	return min(e.PerPage, c.api().maxPerPage)
+}
+
+// do sends a request to url with body marshalled as JSON, unless nil, and decodes the
+// response into out, unless nil. Requests to a Capability the account lacks fail with
+// ErrNotSupported, see Client.Capabilities. GETs go through the WithRequestCoalescing
+// and WithStaleFallback machinery when enabled.
+func (c *Client) do(ctx context.Context, method, url string, body interface{}, out interface{}) (resp *http.Response, err error) {
+	if err = c.supports(url); err != nil {
+		return
+	}
+
+	if method == http.MethodGet && (c.flights != nil || c.fallback != nil) {
+		var b []byte
+		b, resp, err = c.get(ctx, url)
+		if err != nil || out == nil {
+			return
+		}
+
+		return resp, c.decodeBody(bytes.NewReader(b), out)
+	}
+
+	payload := ""
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = string(b)
+	}
+
+	resp, err = c.fetch(ctx, method, url, payload)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return
+	}
+
+	err = c.decodeBody(resp.Body, out)
+
+	return
+}
+
+// get GETs url and reads the body, sharing the request with identical concurrent ones
+// and falling back on a stale body per the client options.
+func (c *Client) get(ctx context.Context, url string) (b []byte, resp *http.Response, err error) {
+	key := flightKey(url)
+	fetch := func() (b []byte, resp *http.Response, err error) {
+		resp, err = c.fetch(ctx, http.MethodGet, url, "")
+		if err != nil {
+			return
+		}
+		b, err = readAll(resp)
+
+		return
+	}
+
+	if c.flights != nil {
+		b, resp, err = c.flights.do(ctx, key, fetch)
+	} else {
+		b, resp, err = fetch()
+	}
+
+	if c.fallback == nil {
+		return
+	}
+
+	if err == nil {
+		c.fallback.store(key, b, resp)
+		return
+	}
+
+	if unreachable(ctx, resp, err) {
+		if stale, staleResp, ok := c.fallback.serve(ctx, key); ok {
+			return stale, staleResp, nil
+		}
+	}
+
+	return
+}
+
+// fetch sends a request to url with the client's credentials and retries.
+func (c *Client) fetch(ctx context.Context, method, url, payload string) (resp *http.Response, err error) {
+	fetcher, err := utils.NewFetchOpts(url, method, payload, c.headers(), c.MaxRetries)
+	if err != nil {
+		return
+	}
+
+	return c.send(ctx, fetcher)
+}
+
+// send performs fetcher under ctx, reporting its retries.
+func (c *Client) send(ctx context.Context, fetcher utils.FetchOpts) (resp *http.Response, err error) {
+	fetcher.Context = ctx
+	fetcher.Client = c.httpClient
+
+	stats := &utils.RetryStats{}
+	fetcher.Stats = stats
+
+	start := time.Now()
+	resp, err = fetcher.Fetch()
+	c.observe(fetcher.Method, fetcher.URL, resp, *stats, time.Since(start)-stats.Backoff)
+	err = c.apiError(fetcher.Method, fetcher.URL, resp, err)
+
+	return
+}
+
+// decodeBody decodes a response body into out, an empty body leaves out untouched.
+func (c *Client) decodeBody(r io.Reader, out interface{}) error {
+	if err := c.decode(r, out); err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// Endpoints wrapped by the Client methods.
+var (
+	projectsEndpoint = Endpoint[*Projects, *Project]{
+		Path:    "/projects",
+		PerPage: 201,
+		Wrap: func(data []*Project, paging *Paging) *Projects {
+			return &Projects{Data: data, Paging: paging}
+		},
+	}
+	usersEndpoint = Endpoint[*Users, *User]{
+		Path:    "/users",
+		PerPage: 201,
+		Wrap: func(data []*User, paging *Paging) *Users {
+			return &Users{Data: data, Paging: paging}
+		},
+	}
+	projectUsersEndpoint = Endpoint[*Users, *User]{
+		Path: "/projects/%d/users",
+		Wrap: usersEndpoint.Wrap,
+	}
+	timeEntriesEndpoint = Endpoint[*TimeEntries, *TimeEntry]{
+		Path: "/time_entries",
+		Wrap: func(data []*TimeEntry, paging *Paging) *TimeEntries {
+			return &TimeEntries{Data: data, Paging: paging}
+		},
+	}
+	userTimeEntriesEndpoint = Endpoint[*TimeEntries, *TimeEntry]{
+		Path: "/users/%d/time_entries",
+		Wrap: timeEntriesEndpoint.Wrap,
+	}
+	projectTimeEntriesEndpoint = Endpoint[*TimeEntries, *TimeEntry]{
+		Path: "/projects/%d/time_entries",
+		Wrap: timeEntriesEndpoint.Wrap,
+	}
+	budgetItemsEndpoint = Endpoint[*BudgetItems, *BudgetItem]{
+		Path: "/projects/%d/budget_items",
+		Wrap: func(data []*BudgetItem, paging *Paging) *BudgetItems {
+			return &BudgetItems{Data: data, Paging: paging}
+		},
+	}
+	userExpenseItemsEndpoint = Endpoint[*ExpenseItems, *ExpenseItem]{
+		Path: "/users/%d/expense_items",
+		Wrap: func(data []*ExpenseItem, paging *Paging) *ExpenseItems {
+			return &ExpenseItems{Data: data, Paging: paging}
+		},
+	}
+	projectExpenseItemsEndpoint = Endpoint[*ExpenseItems, *ExpenseItem]{
+		Path: "/projects/%d/expense_items",
+		Wrap: userExpenseItemsEndpoint.Wrap,
+	}
+	userAssignmentsEndpoint = Endpoint[*Assignments, *Assignment]{
+		Path:    "/users/%d/assignments",
+		PerPage: 250,
+		Wrap: func(data []*Assignment, paging *Paging) *Assignments {
+			return &Assignments{Data: data, Paging: paging}
+		},
+	}
+	projectAssignmentsEndpoint = Endpoint[*Assignments, *Assignment]{
+		Path:    "/projects/%d/assignments",
+		PerPage: 250,
+		Wrap:    userAssignmentsEndpoint.Wrap,
+	}
+	phasesEndpoint = Endpoint[*Phases, *Phase]{
+		Path: "/projects/%d/phases",
+		Wrap: func(data []*Phase, paging *Paging) *Phases {
+			return &Phases{Data: data, Paging: paging}
+		},
+	}
+	userTagsEndpoint = Endpoint[*Tags, *Tag]{
+		Path: "/users/%d/tags",
+		Wrap: func(data []*Tag, paging *Paging) *Tags {
+			return &Tags{Data: data, Paging: paging}
+		},
+	}
+	projectTagsEndpoint = Endpoint[*Tags, *Tag]{
+		Path: "/projects/%d/tags",
+		Wrap: userTagsEndpoint.Wrap,
+	}
+	leaveTypesEndpoint = Endpoint[*LeaveTypes, *LeaveType]{
+		Path: "/leave_types",
+		Wrap: func(data []*LeaveType, paging *Paging) *LeaveTypes {
+			return &LeaveTypes{Data: data, Paging: paging}
+		},
+	}
+	rolesEndpoint = Endpoint[*Roles, *Role]{
+		Path: "/roles",
+		Wrap: func(data []*Role, paging *Paging) *Roles {
+			return &Roles{Data: data, Paging: paging}
+		},
+	}
+	projectBillRatesEndpoint = Endpoint[*BillRates, *BillRate]{
+		Path: "/projects/%d/bill_rates",
+		Wrap: func(data []*BillRate, paging *Paging) *BillRates {
+			return &BillRates{Data: data, Paging: paging}
+		},
+	}
+	approvalsEndpoint = Endpoint[*Approvals, *Approval]{
+		Path: "/approvals",
+		Wrap: func(data []*Approval, paging *Paging) *Approvals {
+			return &Approvals{Data: data, Paging: paging}
+		},
+	}
+	holidaysEndpoint = Endpoint[*Holidays, *Holiday]{
+		Path: "/holidays",
+		Wrap: func(data []*Holiday, paging *Paging) *Holidays {
+			return &Holidays{Data: data, Paging: paging}
+		},
+	}
+	disciplinesEndpoint = Endpoint[*Disciplines, *Discipline]{
+		Path: "/disciplines",
+		Wrap: func(data []*Discipline, paging *Paging) *Disciplines {
+			return &Disciplines{Data: data, Paging: paging}
+		},
+	}
+)
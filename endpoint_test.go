@@ -0,0 +1,260 @@
+package tenkft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testWidget struct {
+	ID int `json:"id"`
+}
+
+type testWidgets struct {
+	Data   []*testWidget
+	Paging *Paging
+}
+
+func TestEndpointListAll(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/things/7/widgets" || r.URL.Query().Get("per_page") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"data": [{"id": 3}], "paging": {"self": "/things/7/widgets?page=2&per_page=2", "next": null}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": 1, "next": "/things/7/widgets?page=2"}}`)
+	}))
+	defer srv.Close()
+	var progress []int
+	client := &Client{token: "test", env: srv.URL, OnProgress: func(fetched, total, page int) {
+		progress = append(progress, fetched, page)
+	}}
+
+	widgets := Endpoint[*testWidgets, *testWidget]{
+		Path:    "/things/%d/widgets",
+		PerPage: 2,
+		Wrap: func(data []*testWidget, paging *Paging) *testWidgets {
+			return &testWidgets{Data: data, Paging: paging}
+		},
+	}
+
+	opts := map[string]string{}
+	all, _, err := widgets.ListAll(context.Background(), client, opts, 7)
+	if err != nil {
+		t.Fatal("could not list all widgets", err)
+	}
+
+	if len(all.Data) != 3 || all.Data[2].ID != 3 {
+		t.Errorf("expected 3 widgets, got %+v", all.Data)
+	}
+	if all.Paging.Page != 2 || all.Paging.PerPage != 2 || all.Paging.TotalPages != 2 || all.Paging.TotalRecords != 3 {
+		t.Errorf("expected page 2 of 2 with 3 records, got %+v", all.Paging)
+	}
+	if len(opts) != 0 {
+		t.Errorf("expected opts not to be modified, got %v", opts)
+	}
+	if fmt.Sprint(progress) != "[2 1 3 2]" {
+		t.Errorf("expected progress after both pages, got %v", progress)
+	}
+}
+
+func TestEndpointCount(t *testing.T) {
+	for _, total := range []int{0, 1, 2, 5, 37, 64} {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Query().Get("per_page") != "1" {
+				http.Error(w, "expected per_page=1", http.StatusBadRequest)
+				return
+			}
+
+			var page int
+			fmt.Sscan(r.URL.Query().Get("page"), &page)
+			if page > total {
+				fmt.Fprintf(w, `{"data": [], "paging": {"page": %d, "next": null}}`, page)
+				return
+			}
+
+			next := "null"
+			if page < total {
+				next = fmt.Sprintf(`"/widgets?page=%d"`, page+1)
+			}
+			fmt.Fprintf(w, `{"data": [{"id": %d}], "paging": {"page": %d, "next": %s}}`, page, page, next)
+		}))
+		client := &Client{token: "test", env: srv.URL}
+
+		widgets := Endpoint[*testWidgets, *testWidget]{
+			Path: "/widgets",
+			Wrap: func(data []*testWidget, paging *Paging) *testWidgets {
+				return &testWidgets{Data: data, Paging: paging}
+			},
+		}
+
+		n, _, err := widgets.Count(context.Background(), client, map[string]string{})
+		srv.Close()
+		if err != nil {
+			t.Fatal("could not count widgets", err)
+		}
+
+		if n != total {
+			t.Errorf("expected %d widgets, got %d", total, n)
+		}
+		if requests > 16 {
+			t.Errorf("expected counting %d widgets to be cheap, took %d requests", total, requests)
+		}
+	}
+}
+
+func TestEndpointListAllCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		fmt.Fprintf(w, `{"data": [{"id": %s}], "paging": {"page": %s, "next": "/widgets?page=next"}}`, page, page)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &Client{token: "test", env: srv.URL, OnProgress: func(fetched, total, page int) {
+		if fetched == 2 {
+			cancel()
+		}
+	}}
+
+	widgets := Endpoint[*testWidgets, *testWidget]{
+		Path: "/widgets",
+		Wrap: func(data []*testWidget, paging *Paging) *testWidgets {
+			return &testWidgets{Data: data, Paging: paging}
+		},
+	}
+
+	all, _, err := widgets.ListAll(ctx, client, map[string]string{})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(all.Data) != 2 {
+		t.Errorf("expected the 2 widgets fetched before canceling, got %+v", all.Data)
+	}
+}
+
+func TestEndpointListAllLoop(t *testing.T) {
+	for name, body := range map[string]string{
+		"same page":  `{"data": [{"id": 1}], "paging": {"page": 1, "next": "/widgets?page=2"}}`,
+		"same self":  `{"data": [{"id": 1}], "paging": {"self": "/widgets?per_page=50", "next": "/widgets?page=2"}}`,
+		"no paging":  `{"data": [{"id": 1}], "paging": {"next": "/widgets?page=2"}}`,
+		"next again": `{"data": [{"id": 1}], "paging": {"page": 2, "next": "/widgets?page=2"}}`,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+		client := &Client{token: "test", env: srv.URL}
+
+		widgets := Endpoint[*testWidgets, *testWidget]{
+			Path: "/widgets",
+			Wrap: func(data []*testWidget, paging *Paging) *testWidgets {
+				return &testWidgets{Data: data, Paging: paging}
+			},
+		}
+
+		all, _, err := widgets.ListAll(context.Background(), client, map[string]string{})
+		var loop *PaginationLoopError
+		if !errors.As(err, &loop) || loop.URL != srv.URL+"/widgets" {
+			t.Errorf("%v: expected a PaginationLoopError, got %v", name, err)
+		}
+		if len(all.Data) > 2 {
+			t.Errorf("%v: expected pagination to stop, got %v widgets", name, len(all.Data))
+		}
+		srv.Close()
+	}
+}
+
+func TestEmptyCollections(t *testing.T) {
+	for _, body := range []string{`{"data": null}`, `{}`} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+		client := &Client{token: "test", env: srv.URL}
+		opts := map[string]string{}
+
+		type result struct {
+			list interface{}
+			err  error
+		}
+		got := func(list interface{}, _ *http.Response, err error) result {
+			return result{list, err}
+		}
+
+		results := map[string]result{
+			"projects":      got(client.GetProjects(opts)),
+			"all projects":  got(client.GetAllProjects(opts)),
+			"users":         got(client.GetUsers(opts)),
+			"all users":     got(client.GetAllUsers(opts)),
+			"project users": got(client.GetProjectUsers(1, opts)),
+			"time entries":  got(client.GetTimeEntries(opts)),
+			"assignments":   got(client.GetAllUserAssignments(&User{ID: 1}, opts)),
+			"phases":        got(client.GetProjectPhases(&Project{ID: 1}, opts)),
+			"leave types":   got(client.GetAllLeaveTypes(opts)),
+			"roles":         got(client.GetRoles(opts)),
+			"bill rates":    got(client.GetAllProjectBillRates(1, opts)),
+			"approvals":     got(client.GetApprovals(opts)),
+			"holidays":      got(client.GetHolidays(opts)),
+			"disciplines":   got(client.GetAllDisciplines(opts)),
+		}
+
+		for name, r := range results {
+			if r.err != nil {
+				t.Errorf("%v from %v: %v", name, body, r.err)
+				continue
+			}
+
+			v := reflect.ValueOf(r.list).Elem()
+			if v.FieldByName("Data").IsNil() || v.FieldByName("Paging").IsNil() {
+				t.Errorf("expected %v from %v to have non-nil data and paging, got %+v", name, body, r.list)
+			}
+		}
+		srv.Close()
+	}
+}
+
+func TestEmptyNestedCollections(t *testing.T) {
+	var projects Projects
+	if err := json.Unmarshal([]byte(`{"data": [{"id": 1, "tags": {"data": null}}]}`), &projects); err != nil {
+		t.Fatal(err)
+	}
+	if projects.Paging == nil {
+		t.Error("expected the projects to have a non-nil paging")
+	}
+	p := projects.Data[0]
+	if p.Tags.Data == nil || p.Tags.Paging == nil || p.Assignments.Data == nil || p.CustomFieldValues.Paging == nil {
+		t.Errorf("expected the project collections to be non-nil, got %+v", p)
+	}
+
+	var users Users
+	if err := json.Unmarshal([]byte(`{"data": null, "paging": null}`), &users); err != nil || users.Data == nil || users.Paging == nil {
+		t.Errorf("expected empty users to be non-nil, got %+v, %v", users, err)
+	}
+
+	u := &User{}
+	if err := json.Unmarshal([]byte(`{"id": 1, "assignments": {"data": null}}`), u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Assignments.Data == nil || u.Availabilities.Data == nil || u.Availabilities.Paging == nil || u.Tags.Data == nil {
+		t.Errorf("expected the user collections to be non-nil, got %+v", u)
+	}
+
+	if b, _ := json.Marshal(u.Availabilities); !strings.HasPrefix(string(b), `{"data":[],`) {
+		t.Errorf("expected empty availabilities to encode as [], got %s", b)
+	}
+}
@@ -0,0 +1,42 @@
+package tenkft
+
+import "context"
+
+// Enricher joins data from outside 10000ft, e.g. an HRIS or LDAP, onto users as they
+// are fetched, typically into their Directory. It gets every user of a call at once so
+// that it can look them up in bulk.
+type Enricher interface {
+	Enrich(ctx context.Context, users []*User) error
+}
+
+// EnricherFunc adapts a function to an Enricher.
+type EnricherFunc func(ctx context.Context, users []*User) error
+
+// Enrich calls f.
+func (f EnricherFunc) Enrich(ctx context.Context, users []*User) error {
+	return f(ctx, users)
+}
+
+// WithEnricher runs e on the users returned by GetUser, GetUsers, GetAllUsers,
+// GetProjectUsers and GetAllUsersWithAssignments, and so on those exported and passed
+// to reports. Enrichers run in the order they were given.
+func WithEnricher(e Enricher) ClientOption {
+	return func(c *Client) {
+		c.enrichers = append(c.enrichers, e)
+	}
+}
+
+// enrich runs the client's enrichers on users.
+func (c *Client) enrich(ctx context.Context, users ...*User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	for _, e := range c.enrichers {
+		if err := e.Enrich(ctx, users); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
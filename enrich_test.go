@@ -0,0 +1,41 @@
+package tenkft
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnricher(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/users": `{"data": [{"id": 1, "email": "ada@example.com"}, {"id": 2, "email": "bob@example.com"}], "paging": {}}`,
+	})
+
+	departments := map[string]string{"ada@example.com": "Engineering"}
+	calls := 0
+	WithEnricher(EnricherFunc(func(ctx context.Context, users []*User) error {
+		calls++
+		for _, u := range users {
+			if d, ok := departments[u.Email]; ok {
+				u.Directory = map[string]string{"department": d}
+			}
+		}
+		return nil
+	}))(client)
+
+	users, _, err := client.GetUsers(map[string]string{})
+	if err != nil {
+		t.Fatal("could not get users", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected a single call for the whole page, got %v", calls)
+	}
+	if users.Data[0].Directory["department"] != "Engineering" || users.Data[1].Directory != nil {
+		t.Errorf("expected only ada to be enriched, got %v and %v", users.Data[0].Directory, users.Data[1].Directory)
+	}
+	clone := users.Data[0].Clone()
+	clone.Directory["department"] = "Sales"
+	if users.Data[0].Directory["department"] != "Engineering" {
+		t.Error("expected clones not to share the directory")
+	}
+}
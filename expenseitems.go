@@ -0,0 +1,92 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+)
+
+// GetUserExpenseItems abstraction to GET /users/<id>/expense_items
+func (c *Client) GetUserExpenseItems(u *User, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return c.GetUserExpenseItemsCtx(context.Background(), u, opts)
+}
+
+// GetUserExpenseItemsCtx is GetUserExpenseItems honouring ctx.
+func (c *Client) GetUserExpenseItemsCtx(ctx context.Context, u *User, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return userExpenseItemsEndpoint.List(ctx, c, opts, int(u.ID))
+}
+
+// GetAllUserExpenseItems returns all expense items of a user - automatically paginates
+// and returns accumulated expense items. resp and err correspond to the latest one in
+// the loop.
+func (c *Client) GetAllUserExpenseItems(u *User, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return c.GetAllUserExpenseItemsCtx(context.Background(), u, opts)
+}
+
+// GetAllUserExpenseItemsCtx is GetAllUserExpenseItems honouring ctx, see
+// Endpoint.ListAll for what is returned when it is canceled.
+func (c *Client) GetAllUserExpenseItemsCtx(ctx context.Context, u *User, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return userExpenseItemsEndpoint.ListAll(ctx, c, opts, int(u.ID))
+}
+
+// GetProjectExpenseItems abstraction to GET /projects/<id>/expense_items
+func (c *Client) GetProjectExpenseItems(p *Project, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return c.GetProjectExpenseItemsCtx(context.Background(), p, opts)
+}
+
+// GetProjectExpenseItemsCtx is GetProjectExpenseItems honouring ctx.
+func (c *Client) GetProjectExpenseItemsCtx(ctx context.Context, p *Project, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return projectExpenseItemsEndpoint.List(ctx, c, opts, int(p.ID))
+}
+
+// GetAllProjectExpenseItems returns all expense items of a project - automatically
+// paginates and returns accumulated expense items. resp and err correspond to the latest
+// one in the loop.
+func (c *Client) GetAllProjectExpenseItems(p *Project, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return c.GetAllProjectExpenseItemsCtx(context.Background(), p, opts)
+}
+
+// GetAllProjectExpenseItemsCtx is GetAllProjectExpenseItems honouring ctx, see
+// Endpoint.ListAll for what is returned when it is canceled.
+func (c *Client) GetAllProjectExpenseItemsCtx(ctx context.Context, p *Project, opts map[string]string) (*ExpenseItems, *http.Response, error) {
+	return projectExpenseItemsEndpoint.ListAll(ctx, c, opts, int(p.ID))
+}
+
+// GetExpenseItem abstraction to GET /users/<id>/expense_items/<id>
+func (c *Client) GetExpenseItem(userID UserID, id int, opts map[string]string) (*ExpenseItem, *http.Response, error) {
+	return c.GetExpenseItemCtx(context.Background(), userID, id, opts)
+}
+
+// GetExpenseItemCtx is GetExpenseItem honouring ctx.
+func (c *Client) GetExpenseItemCtx(ctx context.Context, userID UserID, id int, opts map[string]string) (*ExpenseItem, *http.Response, error) {
+	return userExpenseItemsEndpoint.Get(ctx, c, id, opts, int(userID))
+}
+
+// CreateExpenseItem abstraction to POST /users/<id>/expense_items for ei.UserID
+func (c *Client) CreateExpenseItem(ei *ExpenseItem) (*http.Response, error) {
+	return c.CreateExpenseItemCtx(context.Background(), ei)
+}
+
+// CreateExpenseItemCtx is CreateExpenseItem honouring ctx.
+func (c *Client) CreateExpenseItemCtx(ctx context.Context, ei *ExpenseItem) (*http.Response, error) {
+	return userExpenseItemsEndpoint.Create(ctx, c, ei.baseExpenseItem, ei, int(ei.UserID))
+}
+
+// UpdateExpenseItem abstraction to PUT /users/<id>/expense_items/<id>
+func (c *Client) UpdateExpenseItem(ei *ExpenseItem) (*http.Response, error) {
+	return c.UpdateExpenseItemCtx(context.Background(), ei)
+}
+
+// UpdateExpenseItemCtx is UpdateExpenseItem honouring ctx.
+func (c *Client) UpdateExpenseItemCtx(ctx context.Context, ei *ExpenseItem) (*http.Response, error) {
+	return userExpenseItemsEndpoint.Update(ctx, c, ei.ID, ei.baseExpenseItem, ei, int(ei.UserID))
+}
+
+// DeleteExpenseItem abstraction to DELETE /users/<id>/expense_items/<id>
+func (c *Client) DeleteExpenseItem(ei *ExpenseItem) (*http.Response, error) {
+	return c.DeleteExpenseItemCtx(context.Background(), ei)
+}
+
+// DeleteExpenseItemCtx is DeleteExpenseItem honouring ctx.
+func (c *Client) DeleteExpenseItemCtx(ctx context.Context, ei *ExpenseItem) (*http.Response, error) {
+	return userExpenseItemsEndpoint.Delete(ctx, c, ei.ID, int(ei.UserID))
+}
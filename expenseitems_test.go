@@ -0,0 +1,144 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProjectItems lists and writes the items recorded against projects, checking that
+// only their writable fields are sent.
+func TestProjectItems(t *testing.T) {
+	tests := []struct {
+		name string
+		// routes are the response bodies by "<method> <path>", the one that isn't a GET
+		// being the write.
+		routes map[string]string
+		// query must be sent with the list.
+		query string
+		// run lists and writes the items through client.
+		run func(t *testing.T, client *Client)
+		// sent are fields the write must send, readOnly one it mustn't.
+		sent     map[string]interface{}
+		readOnly string
+	}{
+		{
+			name: "expense items",
+			routes: map[string]string{
+				"GET /projects/42/expense_items": `{"data": [{"id": 1, "user_id": 7, "assignable_id": 42, "amount": 80, "is_suggestion": true}, {"id": 2, "user_id": 7, "assignable_id": 42, "amount": 65.5}], "paging": {"page": 1}}`,
+				"POST /users/7/expense_items":    `{"id": 3, "user_id": 7, "assignable_id": 42, "amount": 12, "date": "2024-03-04"}`,
+			},
+			run: func(t *testing.T, client *Client) {
+				items, _, err := client.GetAllProjectExpenseItems(&Project{ID: 42}, map[string]string{})
+				if err != nil {
+					t.Fatal("could not get expense items", err)
+				}
+				if len(items.Data) != 2 || !items.Data[0].Scheduled() || !items.Data[1].Incurred() || items.Data[1].Amount != 65.5 {
+					t.Fatalf("unexpected expense items %+v", items.Data)
+				}
+
+				ei := NewExpenseItem(7, 42, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), 12)
+				ei.Category = "Travel"
+				if _, err := client.CreateExpenseItem(ei); err != nil {
+					t.Fatal("could not create expense item", err)
+				}
+				if ei.ID != 3 {
+					t.Errorf("expected the created expense item to be decoded, got %+v", ei)
+				}
+			},
+			sent:     map[string]interface{}{"date": "2024-03-04", "category": "Travel"},
+			readOnly: "user_id",
+		},
+		{
+			name: "time entries",
+			routes: map[string]string{
+				"GET /projects/42/time_entries": `{"data": [{"id": 1, "user_id": 7, "assignable_id": 42, "hours": 2.5, "date": "2024-03-04"}], "paging": {"page": 1}}`,
+				"POST /users/7/time_entries":    `{"id": 2, "user_id": 7, "assignable_id": 42, "hours": 3, "date": "2024-03-05"}`,
+			},
+			run: func(t *testing.T, client *Client) {
+				entries, _, err := client.GetAllProjectTimeEntries(&Project{ID: 42}, map[string]string{})
+				if err != nil {
+					t.Fatal("could not get time entries", err)
+				}
+				if len(entries.Data) != 1 || entries.Data[0].ID != 1 || entries.Data[0].Hours != 2.5 {
+					t.Errorf("unexpected time entries %+v", entries.Data)
+				}
+
+				te := &TimeEntry{UserID: 7, AssignableID: 42, Hours: 3, Date: "2024-03-05"}
+				if _, err := client.CreateTimeEntry(te); err != nil {
+					t.Fatal("could not create time entry", err)
+				}
+				if te.ID != 2 {
+					t.Errorf("expected the created time entry to be decoded, got %+v", te)
+				}
+			},
+			sent:     map[string]interface{}{"hours": 3.0, "assignable_id": 42.0},
+			readOnly: "id",
+		},
+		{
+			name: "budget items",
+			routes: map[string]string{
+				"GET /projects/42/budget_items":   `{"data": [{"id": 1, "assignable_id": 42, "item_type": "Expenses", "amount": 250, "category": "Travel"}], "paging": {"page": 1}}`,
+				"PUT /projects/42/budget_items/1": `{"id": 1, "assignable_id": 42, "item_type": "Expenses", "amount": 300, "category": "Travel"}`,
+			},
+			query: "item_type=" + BudgetExpenses,
+			run: func(t *testing.T, client *Client) {
+				items, _, err := client.GetAllProjectBudgetItems(&Project{ID: 42}, map[string]string{"item_type": BudgetExpenses})
+				if err != nil {
+					t.Fatal("could not get budget items", err)
+				}
+				if len(items.Data) != 1 || items.Data[0].Category != "Travel" || items.Data[0].Amount != 250 {
+					t.Fatalf("unexpected budget items %+v", items.Data)
+				}
+
+				item := items.Data[0]
+				item.Amount = 300
+				if _, err := client.UpdateProjectBudgetItem(item); err != nil {
+					t.Fatal("could not update budget item", err)
+				}
+
+				if bi := NewBudgetItem(BudgetTimeFees, 1000); bi.baseBudgetItem == nil || bi.ItemType != BudgetTimeFees {
+					t.Errorf("unexpected budget item %+v", bi)
+				}
+			},
+			sent:     map[string]interface{}{"amount": 300.0, "item_type": BudgetExpenses},
+			readOnly: "id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.routes[r.Method+" "+r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				if r.Method == http.MethodGet && !strings.Contains(r.URL.RawQuery, tt.query) {
+					t.Errorf("expected %v to be sent, got %v", tt.query, r.URL.RawQuery)
+				}
+				if r.Method != http.MethodGet {
+					json.NewDecoder(r.Body).Decode(&sent)
+				}
+				fmt.Fprint(w, body)
+			}))
+			defer srv.Close()
+
+			tt.run(t, &Client{token: "test", env: srv.URL})
+
+			if _, ok := sent[tt.readOnly]; ok {
+				t.Errorf("expected %v not to be sent, got %v", tt.readOnly, sent)
+			}
+			for k, v := range tt.sent {
+				if sent[k] != v {
+					t.Errorf("expected %v to be sent as %v, got %v", k, v, sent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Anonymize returns a Transform replacing the personal data of users - names, email
+// and phone numbers - with pseudonyms derived from a keyed hash. The same value and
+// key always give the same pseudonym, so records stay joinable across exports, while
+// the original can't be recovered without the key. Records other than *tenkft.User
+// pass through unchanged; users are copied rather than modified.
+func Anonymize(key []byte) Transform {
+	pseudonym := func(prefix, value string) string {
+		if value == "" {
+			return ""
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(value))
+
+		return prefix + hex.EncodeToString(mac.Sum(nil))[:12]
+	}
+
+	return func(r Record) (Record, error) {
+		u, ok := r.(*tenkft.User)
+		if !ok {
+			return r, nil
+		}
+
+		anon := u.Clone()
+		anon.DisplayName = pseudonym("person-", u.DisplayName)
+		anon.OfficePhone = pseudonym("phone-", u.OfficePhone)
+		anon.FirstName = pseudonym("first-", anon.FirstName)
+		anon.LastName = pseudonym("last-", anon.LastName)
+		anon.MobilePhone = pseudonym("phone-", anon.MobilePhone)
+		if anon.Email != "" {
+			anon.Email = pseudonym("", anon.Email) + "@example.invalid"
+		}
+
+		return anon, nil
+	}
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestAnonymize(t *testing.T) {
+	anonymize := Anonymize([]byte("secret"))
+
+	u := tenkft.NewUser()
+	u.ID = 1
+	u.FirstName, u.Email, u.DisplayName = "Ada", "ada@example.com", "Ada Lovelace"
+
+	r, err := anonymize(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anon := r.(*tenkft.User)
+
+	if anon.FirstName == "Ada" || anon.DisplayName == "Ada Lovelace" || anon.Email == "ada@example.com" {
+		t.Errorf("expected personal data to be replaced, got %+v", anon)
+	}
+	if anon.ID != 1 {
+		t.Errorf("expected the id to be kept, got %v", anon.ID)
+	}
+	if u.FirstName != "Ada" {
+		t.Error("expected the original user not to be modified")
+	}
+
+	again, _ := anonymize(u)
+	if again.(*tenkft.User).Email != anon.Email {
+		t.Error("expected pseudonyms to be stable")
+	}
+}
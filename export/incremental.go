@@ -0,0 +1,282 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PartitionFormat is the layout of the date partition directories incremental exports
+// are written to, e.g. dt=2024-03-01.
+const PartitionFormat = "dt=2006-01-02"
+
+// WatermarkMargin is how long before the start of a run its watermark is set, so that
+// records updated while the run was paginating, and clock skew with the API, are
+// exported again by the next run.
+const WatermarkMargin = 5 * time.Minute
+
+// Manifest lists the files written by the incremental exports of a table, oldest first.
+// It is kept as manifest.json in the table directory.
+type Manifest struct {
+	Table string         `json:"table"`
+	Files []ManifestFile `json:"files"`
+	// Watermark is the start of the last run minus WatermarkMargin, the next run only
+	// exports records updated at or after it. Runs overlap, so a record may be in
+	// several files: the warehouse should dedupe on id and updated_at.
+	Watermark string `json:"watermark,omitempty"`
+}
+
+// ManifestFile is a file written by one run of an incremental export.
+type ManifestFile struct {
+	// Path is relative to the table directory, e.g. dt=2024-03-01/projects-20240301T020000Z.ndjson.
+	Path    string `json:"path"`
+	Records int    `json:"records"`
+	Since   string `json:"since,omitempty"`
+	// Until is the newest updated_at in the file.
+	Until     string    `json:"until"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IncrementalSink writes the records updated since the previous run of a table's
+// export, see NewIncrementalSink.
+type IncrementalSink struct {
+	*JSONLinesSink
+	f        *os.File
+	dir      string
+	now      time.Time
+	manifest Manifest
+	since    time.Time
+	until    string
+	newest   time.Time
+	records  int
+}
+
+// NewIncrementalSink prepares a run of the incremental export of table into dir/<table>,
+// run at now, the time the run started. Records updated before the manifest's Watermark
+// are dropped, the others are written to the dt=<date of now> partition once Commit is
+// called. <table>.schema.json and <table>.sql are written next to the manifest, see
+// NewTableSink.
+//
+// Records must encode an updated_at, in RFC 3339 format.
+func NewIncrementalSink(dir, table string, record interface{}, now time.Time) (*IncrementalSink, error) {
+	dir = filepath.Join(dir, table)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &IncrementalSink{dir: dir, now: now.UTC(), manifest: Manifest{Table: table}}
+
+	var err error
+	if s.manifest, err = readManifest(dir, table); err != nil {
+		return nil, err
+	}
+
+	if s.manifest.Watermark != "" {
+		if s.since, err = time.Parse(time.RFC3339, s.manifest.Watermark); err != nil {
+			return nil, fmt.Errorf("manifest watermark: %w", err)
+		}
+	}
+
+	if err := writeSchema(dir, table, record); err != nil {
+		return nil, err
+	}
+
+	// Rows go to a fixed file that Commit moves to its partition, so that a failed run
+	// is overwritten by the next one.
+	if s.f, err = os.Create(s.partial()); err != nil {
+		return nil, err
+	}
+	s.JSONLinesSink = NewJSONLinesSink(s.f)
+
+	return s, nil
+}
+
+// Since returns the watermark of the previous run, the zero time on the first one.
+// Sources may use it to only fetch the records updated after it.
+func (s *IncrementalSink) Since() time.Time {
+	return s.since
+}
+
+// Write writes r when it was updated at or after the watermark of the previous run.
+func (s *IncrementalSink) Write(ctx context.Context, r Record) error {
+	updated, err := updatedAt(r)
+	if err != nil {
+		return err
+	}
+
+	if !s.since.IsZero() && updated.Before(s.since) {
+		return nil
+	}
+
+	if updated.After(s.newest) {
+		s.newest = updated
+		s.until = updated.Format(time.RFC3339)
+	}
+	s.records++
+
+	return s.JSONLinesSink.Write(ctx, r)
+}
+
+// Close flushes the rows and closes the file.
+func (s *IncrementalSink) Close() error {
+	if err := s.JSONLinesSink.Close(); err != nil {
+		s.f.Close()
+		return err
+	}
+
+	return s.f.Close()
+}
+
+// Commit moves the rows written into their partition and records them in the manifest,
+// advancing its watermark. It must only be called once the pipeline succeeded, records
+// of a failed run being exported again by the next one. No file is recorded when no
+// record was updated.
+func (s *IncrementalSink) Commit() error {
+	watermark := s.now.Add(-WatermarkMargin).Format(time.RFC3339)
+	if s.records == 0 {
+		if err := os.Remove(s.partial()); err != nil {
+			return err
+		}
+		s.manifest.Watermark = watermark
+		return writeManifest(s.dir, s.manifest)
+	}
+
+	path := filepath.Join(s.now.Format(PartitionFormat), fmt.Sprintf("%v-%v.ndjson", s.manifest.Table, s.now.Format("20060102T150405Z")))
+	if err := os.MkdirAll(filepath.Join(s.dir, filepath.Dir(path)), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(s.partial(), filepath.Join(s.dir, path)); err != nil {
+		return err
+	}
+
+	s.manifest.Files = append(s.manifest.Files, ManifestFile{
+		Path:      filepath.ToSlash(path),
+		Records:   s.records,
+		Since:     s.manifest.Watermark,
+		Until:     s.until,
+		CreatedAt: s.now,
+	})
+	s.manifest.Watermark = watermark
+
+	return writeManifest(s.dir, s.manifest)
+}
+
+// Manifest returns the manifest, including this run once committed.
+func (s *IncrementalSink) Manifest() Manifest {
+	return s.manifest
+}
+
+// partial is the file rows are written to until Commit.
+func (s *IncrementalSink) partial() string {
+	return filepath.Join(s.dir, ".partial.ndjson")
+}
+
+// updatedAt returns the updated_at r encodes, the zero time when it is empty so that
+// the record is only exported by the first run.
+func updatedAt(r Record) (t time.Time, err error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	var v struct {
+		UpdatedAt *string `json:"updated_at"`
+	}
+	if err = json.Unmarshal(b, &v); err != nil {
+		return t, fmt.Errorf("%T: %w", r, err)
+	}
+
+	if v.UpdatedAt == nil {
+		return t, fmt.Errorf("%T has no updated_at", r)
+	}
+	if *v.UpdatedAt == "" {
+		return
+	}
+
+	return time.Parse(time.RFC3339, *v.UpdatedAt)
+}
+
+// PurgePartitions deletes the files of the incremental exports of table in dir that were
+// written before cutoff, and their partitions once empty, so that daily exports don't
+// grow unbounded once loaded. The manifest keeps its watermark, so the next run still
+// only exports what was updated since the last one. It returns the files deleted.
+func PurgePartitions(dir, table string, cutoff time.Time) (purged []ManifestFile, err error) {
+	dir = filepath.Join(dir, table)
+	m, err := readManifest(dir, table)
+	if err != nil {
+		return
+	}
+
+	kept := []ManifestFile{}
+	for _, f := range m.Files {
+		if !f.CreatedAt.Before(cutoff) {
+			kept = append(kept, f)
+			continue
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		err = nil
+		// Only removes the partition once its last file is gone.
+		os.Remove(filepath.Dir(path))
+		purged = append(purged, f)
+	}
+
+	if len(purged) == 0 {
+		return
+	}
+
+	// Files not deleted because of err stay listed.
+	m.Files = append(kept, m.Files[len(kept)+len(purged):]...)
+	if writeErr := writeManifest(dir, m); err == nil {
+		err = writeErr
+	}
+
+	return
+}
+
+// readManifest reads the manifest in the table directory dir, an empty one when there is
+// none yet.
+func readManifest(dir, table string) (m Manifest, err error) {
+	m.Table = table
+
+	path := filepath.Join(dir, "manifest.json")
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(b, &m); err != nil {
+		err = fmt.Errorf("%v: %w", path, err)
+	}
+
+	return
+}
+
+// writeManifest replaces the manifest in the table directory dir in one step, so that a
+// reader never sees it half written.
+func writeManifest(dir string, m Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, "manifest.json.tmp")
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, "manifest.json"))
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type row struct {
+	ID        int    `json:"id"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func rows(rs ...row) Source {
+	return func(ctx context.Context, out chan<- Record) error {
+		for _, r := range rs {
+			if err := Send(ctx, out, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func runIncremental(t *testing.T, dir string, now time.Time, commit bool, rs ...row) *IncrementalSink {
+	t.Helper()
+
+	s, err := NewIncrementalSink(dir, "rows", &row{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Pipeline{Source: rows(rs...), Sink: s}
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if commit {
+		if err := s.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s
+}
+
+func TestIncrementalSink(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	runIncremental(t, dir, day1, true,
+		row{1, "2024-02-27T10:00:00Z"},
+		row{2, "2024-02-29T10:00:00Z"},
+	)
+	// A failed run isn't recorded.
+	runIncremental(t, dir, day2, false, row{3, "2024-03-01T09:00:00Z"})
+	s := runIncremental(t, dir, day2, true,
+		row{1, "2024-02-27T10:00:00Z"},
+		row{2, "2024-03-01T11:00:00Z"},
+		row{3, "2024-03-01T09:00:00Z"},
+	)
+
+	m := s.Manifest()
+	if len(m.Files) != 2 || m.Watermark != "2024-03-02T01:55:00Z" {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "rows", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored Manifest
+	if err := json.Unmarshal(b, &stored); err != nil || stored.Watermark != m.Watermark {
+		t.Errorf("expected the manifest to be stored, got %+v, %v", stored, err)
+	}
+
+	f := m.Files[1]
+	if f.Path != "dt=2024-03-02/rows-20240302T020000Z.ndjson" || f.Records != 2 || f.Since != "2024-03-01T01:55:00Z" || f.Until != "2024-03-01T11:00:00Z" {
+		t.Errorf("unexpected file %+v", f)
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "rows", filepath.FromSlash(f.Path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"id":2`) {
+		t.Errorf("expected rows 2 and 3, got %q", b)
+	}
+
+	// Nothing updated, nothing recorded.
+	s = runIncremental(t, dir, day2.AddDate(0, 0, 1), true, row{2, "2024-03-01T11:00:00Z"})
+	if len(s.Manifest().Files) != 2 {
+		t.Errorf("expected an empty run not to be recorded, got %+v", s.Manifest())
+	}
+}
+
+func TestIncrementalSinkLateUpdates(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Row 2 is read early on with its old updated_at, then updated at 11:58 while the
+	// run goes on to read row 1, updated at 11:59.
+	runIncremental(t, dir, start, true,
+		row{2, "2024-03-01T10:00:00Z"},
+		row{1, "2024-03-01T11:59:00Z"},
+	)
+
+	s := runIncremental(t, dir, start.Add(time.Hour), true,
+		row{1, "2024-03-01T11:59:00Z"},
+		row{2, "2024-03-01T11:58:00Z"},
+		// Updated in the very second of the watermark.
+		row{3, "2024-03-01T11:55:00Z"},
+		row{4, "2024-03-01T11:54:59Z"},
+	)
+
+	m := s.Manifest()
+	if len(m.Files) != 2 || m.Files[1].Records != 3 {
+		t.Fatalf("expected rows 1 to 3 to be exported again, got %+v", m)
+	}
+}
+
+func TestPurgePartitions(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	runIncremental(t, dir, day1, true, row{1, "2024-02-29T10:00:00Z"})
+	runIncremental(t, dir, day1.AddDate(0, 0, 1), true, row{2, "2024-03-01T10:00:00Z"})
+
+	purged, err := PurgePartitions(dir, "rows", day1.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0].Path != "dt=2024-03-01/rows-20240301T020000Z.ndjson" {
+		t.Errorf("expected the first day to be purged, got %+v", purged)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "rows", "dt=2024-03-01")); !os.IsNotExist(err) {
+		t.Errorf("expected the partition to be removed, got %v", err)
+	}
+
+	// The next run doesn't export the purged records again.
+	s := runIncremental(t, dir, day1.AddDate(0, 0, 2), true, row{1, "2024-02-29T10:00:00Z"})
+	if m := s.Manifest(); len(m.Files) != 1 || m.Watermark != "2024-03-03T01:55:00Z" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+}
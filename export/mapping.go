@@ -0,0 +1,252 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Mapping selects and renames the fields of exported records, so that extracts can be
+// adjusted by editing a file rather than the code. It is read from JSON:
+//
+//	{
+//		"columns": [
+//			{"source": "id", "column": "project_id"},
+//			{"source": "starts_at", "column": "start", "transforms": ["date:02/01/2006"]},
+//			{"source": "tags.data.value", "column": "tags", "transforms": ["join:;"]}
+//		]
+//	}
+//
+// Sources are paths in the JSON encoding of the records, a path through a list
+// collecting the values of its items.
+type Mapping struct {
+	Columns []Column `json:"columns"`
+}
+
+// Column is an output column of a Mapping.
+type Column struct {
+	// Source is the dot separated path of the field in the record, e.g. "tags.data.value".
+	Source string `json:"source"`
+	// Column is the name of the output column, Source when empty.
+	Column string `json:"column,omitempty"`
+	// Transforms are applied to the value in order:
+	//	date:<layout>  formats a date or timestamp with a Go time layout
+	//	split:<sep>    splits a string into a list
+	//	join:<sep>     joins a list into a string
+	Transforms []string `json:"transforms,omitempty"`
+}
+
+// LoadMapping reads the mapping file at path, rejecting unknown transforms.
+func LoadMapping(path string) (m *Mapping, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	m = &Mapping{}
+	if err = json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+
+	if _, err = m.compile(); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+
+	return
+}
+
+// Row is a record mapped by a Mapping, it encodes to a JSON object with the columns in
+// the order of the mapping.
+type Row struct {
+	Columns []string
+	Values  []interface{}
+}
+
+// Get returns the value of column, nil when there is no such column.
+func (r *Row) Get(column string) interface{} {
+	for i, c := range r.Columns {
+		if c == column {
+			return r.Values[i]
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes r as an object keeping the order of its columns.
+func (r *Row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range r.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Transform returns a Transform mapping records to a *Row. A value failing a transform,
+// e.g. a date that can't be parsed, fails the pipeline.
+func (m *Mapping) Transform() (Transform, error) {
+	steps, err := m.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r Record) (Record, error) {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// Numbers are kept as they are written, large IDs would render as floats, e.g.
+		// 1e+06, once joined.
+		var doc interface{}
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		if err := d.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		row := &Row{}
+		for i, c := range m.Columns {
+			v := lookup(doc, strings.Split(c.Source, "."))
+			for _, step := range steps[i] {
+				if v, err = step(v); err != nil {
+					return nil, fmt.Errorf("column %v: %w", m.columnName(i), err)
+				}
+			}
+
+			row.Columns = append(row.Columns, m.columnName(i))
+			row.Values = append(row.Values, v)
+		}
+
+		return row, nil
+	}, nil
+}
+
+func (m *Mapping) columnName(i int) string {
+	if m.Columns[i].Column == "" {
+		return m.Columns[i].Source
+	}
+
+	return m.Columns[i].Column
+}
+
+type transformStep func(v interface{}) (interface{}, error)
+
+// compile parses the transforms of every column.
+func (m *Mapping) compile() (steps [][]transformStep, err error) {
+	for _, c := range m.Columns {
+		if c.Source == "" {
+			return nil, fmt.Errorf("column %q has no source", c.Column)
+		}
+
+		var column []transformStep
+		for _, t := range c.Transforms {
+			name, arg, _ := strings.Cut(t, ":")
+			step, ok := transforms[name]
+			if !ok {
+				return nil, fmt.Errorf("column %v: unknown transform %q", c.Source, t)
+			}
+			column = append(column, step(arg))
+		}
+		steps = append(steps, column)
+	}
+
+	return
+}
+
+// transforms build the steps of the Column transforms from their argument.
+var transforms = map[string]func(arg string) transformStep{
+	"date": func(layout string) transformStep {
+		return func(v interface{}) (interface{}, error) {
+			s, _ := v.(string)
+			if s == "" {
+				return v, nil
+			}
+
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				if t, err = time.Parse(tenkft.DateFormat, s); err != nil {
+					return nil, fmt.Errorf("%q is not a date", s)
+				}
+			}
+
+			return t.Format(layout), nil
+		}
+	},
+	"split": func(sep string) transformStep {
+		return func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok || s == "" {
+				return []interface{}{}, nil
+			}
+
+			parts := []interface{}{}
+			for _, p := range strings.Split(s, sep) {
+				parts = append(parts, strings.TrimSpace(p))
+			}
+
+			return parts, nil
+		}
+	},
+	"join": func(sep string) transformStep {
+		return func(v interface{}) (interface{}, error) {
+			list, ok := v.([]interface{})
+			if !ok {
+				return v, nil
+			}
+
+			parts := make([]string, len(list))
+			for i, item := range list {
+				parts[i] = fmt.Sprint(item)
+			}
+
+			return strings.Join(parts, sep), nil
+		}
+	},
+}
+
+// lookup returns the value at path in doc, collecting the values of every item when the
+// path goes through a list. nil when the path doesn't exist.
+func lookup(doc interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return doc
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		return lookup(v[path[0]], path[1:])
+	case []interface{}:
+		values := []interface{}{}
+		for _, item := range v {
+			if value := lookup(item, path); value != nil {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	err := ioutil.WriteFile(path, []byte(`{"columns": [
+		{"source": "id", "column": "project_id"},
+		{"source": "starts_at", "column": "start", "transforms": ["date:02/01/2006"]},
+		{"source": "tags.data.value", "column": "tags", "transforms": ["join:;"]},
+		{"source": "description", "transforms": ["split:,"]}
+	]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadMapping(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transform, err := m.Transform()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := tenkft.NewProject()
+	p.ID = 7
+	p.StartsAt = "2024-03-01"
+	p.Description = "design, build"
+	p.Tags.Data = []*tenkft.Tag{tenkft.NewTag("fixed"), tenkft.NewTag("emea")}
+
+	r, err := transform(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := json.Marshal(r)
+	if string(b) != `{"project_id":7,"start":"01/03/2024","tags":"fixed;emea","description":["design","build"]}` {
+		t.Errorf("unexpected row %s", b)
+	}
+
+	// Large IDs aren't rendered as floats.
+	p.ID = 1000000
+	p.Tags.Data[0].ID, p.Tags.Data[1].ID = 1234567, 7654321
+	m.Columns = append(m.Columns, Column{Source: "tags.data.id", Column: "tag_ids", Transforms: []string{"join:,"}})
+	if transform, err = m.Transform(); err != nil {
+		t.Fatal(err)
+	}
+	if r, err = transform(p); err != nil {
+		t.Fatal(err)
+	}
+	row := r.(*Row)
+	if id, ids := fmt.Sprint(row.Get("project_id")), row.Get("tag_ids"); id != "1000000" || ids != "1234567,7654321" {
+		t.Errorf("expected the IDs as written, got %v and %v", id, ids)
+	}
+	if b, _ := json.Marshal(r); !strings.HasPrefix(string(b), `{"project_id":1000000,`) {
+		t.Errorf("unexpected row %s", b)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"columns": [{"source": "id", "transforms": ["upper"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMapping(path); err == nil {
+		t.Error("expected an unknown transform to be rejected")
+	}
+}
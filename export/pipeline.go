@@ -0,0 +1,135 @@
+// Package export streams records out of 10000ft through a pipeline of fetch, transform
+// and sink stages connected by bounded channels. A slow sink blocks the stages before
+// it, so fetching naturally slows down instead of buffering the account in memory.
+package export
+
+import (
+	"context"
+	"sync"
+)
+
+// Record is a single exported item, e.g. a *tenkft.Project.
+type Record interface{}
+
+// Source sends records to out until it runs out of them or ctx is canceled. Sends
+// block while the next stage is busy, which is what slows fetching down.
+type Source func(ctx context.Context, out chan<- Record) error
+
+// Transform maps a record to the one passed downstream, returning nil drops it.
+type Transform func(Record) (Record, error)
+
+// Sink consumes the records coming out of the pipeline.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+	// Close is called once after the last record, even when the pipeline failed.
+	Close() error
+}
+
+// SinkFunc adapts a function to a Sink with a no-op Close.
+type SinkFunc func(ctx context.Context, r Record) error
+
+// Write calls f.
+func (f SinkFunc) Write(ctx context.Context, r Record) error {
+	return f(ctx, r)
+}
+
+// Close does nothing.
+func (f SinkFunc) Close() error {
+	return nil
+}
+
+// Pipeline connects a Source to a Sink through Transforms, every stage running in its
+// own goroutine.
+type Pipeline struct {
+	Source     Source
+	Transforms []Transform
+	Sink       Sink
+	// Buffer is the capacity of the channels between stages, 0 makes them unbuffered.
+	Buffer int
+}
+
+// Run runs the pipeline until the source is exhausted, a stage fails or ctx is
+// canceled, and returns the first error.
+func (p *Pipeline) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	in := make(chan Record, p.Buffer)
+	wg.Add(1)
+	go func(out chan<- Record) {
+		defer wg.Done()
+		defer close(out)
+		if err := p.Source(ctx, out); err != nil {
+			fail(err)
+		}
+	}(in)
+
+	for _, t := range p.Transforms {
+		out := make(chan Record, p.Buffer)
+		wg.Add(1)
+		go func(t Transform, in <-chan Record, out chan<- Record) {
+			defer wg.Done()
+			defer close(out)
+			for r := range in {
+				r, err := t(r)
+				if err != nil {
+					fail(err)
+					drain(in)
+					return
+				}
+
+				if r == nil {
+					continue
+				}
+
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					drain(in)
+					return
+				}
+			}
+		}(t, in, out)
+		in = out
+	}
+
+	for r := range in {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		if err := p.Sink.Write(ctx, r); err != nil {
+			fail(err)
+		}
+	}
+
+	wg.Wait()
+
+	if err := p.Sink.Close(); err != nil {
+		fail(err)
+	}
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+
+	return firstErr
+}
+
+// drain discards what is left in a channel so the stage writing to it can return.
+func drain(in <-chan Record) {
+	for range in {
+	}
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func numbers(n int) Source {
+	return func(ctx context.Context, out chan<- Record) error {
+		for i := 1; i <= n; i++ {
+			if err := Send(ctx, out, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Pipeline{
+		Source: numbers(5),
+		Transforms: []Transform{
+			func(r Record) (Record, error) {
+				if r.(int)%2 == 0 {
+					return nil, nil
+				}
+				return r.(int) * 10, nil
+			},
+		},
+		Sink:   NewJSONLinesSink(&buf),
+		Buffer: 1,
+	}
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatal("pipeline failed", err)
+	}
+
+	if buf.String() != "10\n30\n50\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestPipelineStopsOnSinkError(t *testing.T) {
+	boom := errors.New("boom")
+	written := 0
+	p := &Pipeline{
+		Source: numbers(1000),
+		Sink: SinkFunc(func(ctx context.Context, r Record) error {
+			written++
+			if written == 3 {
+				return boom
+			}
+			return nil
+		}),
+	}
+
+	if err := p.Run(context.Background()); err != boom {
+		t.Errorf("expected the sink error, got %v", err)
+	}
+	if written != 3 {
+		t.Errorf("expected writes to stop after the error, got %v", written)
+	}
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Provenance records how an extract was produced, so that a report can be shown to
+// correspond to it: the filters and pages the sources fetched, when, and a SHA-256
+// digest of the records written. Attach it with WithProvenance and wrap the sink with
+// Sink:
+//
+//	prov := &export.Provenance{}
+//	ctx = export.WithProvenance(ctx, prov)
+//	p := &export.Pipeline{Source: export.Projects(c, opts), Sink: prov.Sink(sink)}
+//	if err := p.Run(ctx); err != nil {
+//		return err
+//	}
+//	return prov.WriteFile("projects.provenance.json")
+type Provenance struct {
+	mu sync.Mutex
+
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Fetches are the collections fetched by the sources, in order.
+	Fetches []Fetch `json:"fetches"`
+	// Records is the number of records written by the sink, records it drops, e.g. the
+	// ones IncrementalSink filters out, aren't counted.
+	Records int `json:"records"`
+	// Digest is the hex SHA-256 of the lines written by the sink when it is, or embeds, a
+	// JSONLinesSink, of the records given to the sink, each encoded as a line of JSON,
+	// otherwise.
+	Digest string `json:"digest"`
+}
+
+// Fetch is a collection fetched by a source.
+type Fetch struct {
+	Path    string            `json:"path"`
+	Filters map[string]string `json:"filters"`
+	Pages   []FetchedPage     `json:"pages"`
+}
+
+// FetchedPage is a page of a Fetch.
+type FetchedPage struct {
+	Page    int    `json:"page"`
+	Self    string `json:"self,omitempty"`
+	Records int    `json:"records"`
+}
+
+// provenanceKey is the context key the Provenance of an export is stored under.
+type provenanceKey struct{}
+
+// WithProvenance returns a copy of ctx making the sources run with it record what they
+// fetch into p, and starts p.
+func WithProvenance(ctx context.Context, p *Provenance) context.Context {
+	p.mu.Lock()
+	p.StartedAt = time.Now().UTC()
+	p.mu.Unlock()
+
+	return context.WithValue(ctx, provenanceKey{}, p)
+}
+
+// provenance returns the Provenance attached to ctx, nil if none.
+func provenance(ctx context.Context) *Provenance {
+	p, _ := ctx.Value(provenanceKey{}).(*Provenance)
+
+	return p
+}
+
+// fetch starts recording a fetch of path with opts, returning its index.
+func (p *Provenance) fetch(path string, opts map[string]string) int {
+	filters := map[string]string{}
+	for k, v := range opts {
+		filters[k] = v
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Fetches = append(p.Fetches, Fetch{Path: path, Filters: filters, Pages: []FetchedPage{}})
+
+	return len(p.Fetches) - 1
+}
+
+// page records a page of the fetch i.
+func (p *Provenance) page(i int, paging *tenkft.Paging, records int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Fetches[i].Pages = append(p.Fetches[i].Pages, FetchedPage{Page: paging.Page, Self: paging.Self, Records: records})
+}
+
+// Sink returns a sink writing to s while digesting the records, see Digest.
+func (p *Provenance) Sink(s Sink) Sink {
+	d := &digestSink{Sink: s, p: p, lines: &digestWriter{h: sha256.New()}}
+	if jl, ok := s.(interface{ jsonLines() *JSONLinesSink }); ok {
+		jl.jsonLines().tee(d.lines)
+		d.teed = true
+	}
+
+	return d
+}
+
+// WriteFile writes p as JSON to path.
+func (p *Provenance) WriteFile(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// digestSink hashes the lines Sink writes when teed, the records going to Sink
+// otherwise.
+type digestSink struct {
+	Sink
+	p     *Provenance
+	lines *digestWriter
+	teed  bool
+}
+
+func (s *digestSink) Write(ctx context.Context, r Record) error {
+	if !s.teed {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		s.lines.Write(append(b, '\n'))
+	}
+
+	return s.Sink.Write(ctx, r)
+}
+
+// Close closes the sink and completes the provenance.
+func (s *digestSink) Close() error {
+	s.p.mu.Lock()
+	s.p.Records, s.p.Digest = s.lines.lines, hex.EncodeToString(s.lines.h.Sum(nil))
+	s.p.FinishedAt = time.Now().UTC()
+	s.p.mu.Unlock()
+
+	return s.Sink.Close()
+}
+
+// digestWriter hashes lines of JSON and counts them.
+type digestWriter struct {
+	h     hash.Hash
+	lines int
+}
+
+func (w *digestWriter) Write(b []byte) (int, error) {
+	w.lines += bytes.Count(b, []byte{'\n'})
+
+	return w.h.Write(b)
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// projectPages returns a client served two pages of projects.
+func projectPages(t *testing.T) *tenkft.Client {
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": 1, "self": "/projects?page=1", "next": "/projects?page=2"}}`
+		if r.URL.Query().Get("page") == "2" {
+			body = `{"data": [{"id": 3}], "paging": {"page": 2, "self": "/projects?page=2", "next": null}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+	})
+	c, err := tenkft.NewClient("test", tenkft.Production, tenkft.WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestProvenance(t *testing.T) {
+	c := projectPages(t)
+
+	var buf bytes.Buffer
+	prov := &Provenance{}
+	ctx := WithProvenance(context.Background(), prov)
+	p := &Pipeline{Source: Projects(c, map[string]string{"with_archived": "true"}), Sink: prov.Sink(NewJSONLinesSink(&buf))}
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	if prov.Digest != hex.EncodeToString(sum[:]) || prov.Records != 3 {
+		t.Errorf("expected the digest of the output, got %+v", prov)
+	}
+
+	if len(prov.Fetches) != 1 {
+		t.Fatalf("expected a single fetch, got %+v", prov.Fetches)
+	}
+	f := prov.Fetches[0]
+	if f.Path != "/projects" || f.Filters["with_archived"] != "true" || len(f.Pages) != 2 || f.Pages[1].Self != "/projects?page=2" || f.Pages[1].Records != 1 {
+		t.Errorf("unexpected fetch %+v", f)
+	}
+	if prov.StartedAt.IsZero() || prov.FinishedAt.Before(prov.StartedAt) {
+		t.Errorf("expected the run to be timestamped, got %v - %v", prov.StartedAt, prov.FinishedAt)
+	}
+}
+
+func TestSourceLimits(t *testing.T) {
+	c := projectPages(t)
+
+	var buf bytes.Buffer
+	ctx := tenkft.WithLimits(context.Background(), tenkft.Limits{MaxPages: 1})
+	p := &Pipeline{Source: Projects(c, map[string]string{}), Sink: NewJSONLinesSink(&buf)}
+	if err := p.Run(ctx); !errors.Is(err, tenkft.ErrLimitExceeded) {
+		t.Errorf("expected the sources to apply the limits, got %v", err)
+	}
+}
+
+func TestProvenanceDroppedRecords(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	runIncremental(t, dir, day1, true, row{1, "2024-02-27T10:00:00Z"})
+
+	s, err := NewIncrementalSink(dir, "rows", &row{}, day1.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov := &Provenance{}
+	p := &Pipeline{Source: rows(row{1, "2024-02-27T10:00:00Z"}, row{2, "2024-03-01T11:00:00Z"}), Sink: prov.Sink(s)}
+	if err := p.Run(WithProvenance(context.Background(), prov)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := s.Manifest().Files
+	b, err := ioutil.ReadFile(filepath.Join(dir, "rows", files[len(files)-1].Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The record the sink drops isn't part of the digest.
+	sum := sha256.Sum256(b)
+	if prov.Digest != hex.EncodeToString(sum[:]) || prov.Records != 1 {
+		t.Errorf("expected the digest of the partition written, got %+v", prov)
+	}
+}
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Field is a column of a warehouse table, it marshals to BigQuery's JSON schema format.
+type Field struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	Mode   string  `json:"mode,omitempty"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Schema derives the warehouse columns of a record type, e.g. &tenkft.Project{}, from
+// the JSON encoding of its fields so that it matches what the JSON lines sinks write.
+// Nested objects become RECORD columns, slices REPEATED ones and untyped values JSON.
+func Schema(record interface{}) []Field {
+	return structFields(indirect(reflect.TypeOf(record)), map[reflect.Type]bool{})
+}
+
+func structFields(t reflect.Type, seen map[reflect.Type]bool) []Field {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	fields := []Field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" && indirect(sf.Type).Kind() == reflect.Struct {
+			fields = append(fields, structFields(indirect(sf.Type), seen)...)
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if f, ok := field(name, sf.Type, seen); ok {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func field(name string, t reflect.Type, seen map[reflect.Type]bool) (Field, bool) {
+	t = indirect(t)
+	f := Field{Name: name, Mode: "NULLABLE"}
+
+	if reflect.PointerTo(t).Implements(marshaler) {
+		f.Type = "JSON"
+		return f, true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		f.Type = "STRING"
+	case reflect.Bool:
+		f.Type = "BOOL"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.Type = "INT64"
+	case reflect.Float32, reflect.Float64:
+		f.Type = "FLOAT64"
+	case reflect.Interface, reflect.Map:
+		f.Type = "JSON"
+	case reflect.Struct:
+		f.Type = "RECORD"
+		f.Fields = structFields(t, seen)
+		if len(f.Fields) == 0 {
+			return f, false
+		}
+	case reflect.Slice, reflect.Array:
+		elem, ok := field(name, t.Elem(), seen)
+		if !ok || elem.Mode == "REPEATED" {
+			elem = Field{Name: name, Type: "JSON"}
+		}
+		elem.Mode = "REPEATED"
+		return elem, true
+	default:
+		return f, false
+	}
+
+	return f, true
+}
+
+// marshaler is implemented by types encoding themselves, e.g. tenkft.Settings, whose
+// fields say nothing of their JSON.
+var marshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// DDL renders a BigQuery CREATE TABLE statement for fields.
+func DDL(table string, fields []Field) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%v` (\n%v\n);\n", table, columns(fields, "  "))
+}
+
+func columns(fields []Field, indent string) string {
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		lines[i] = indent + "`" + f.Name + "` " + columnType(f, indent)
+	}
+
+	return strings.Join(lines, ",\n")
+}
+
+func columnType(f Field, indent string) string {
+	typ := f.Type
+	if f.Type == "RECORD" {
+		typ = "STRUCT<\n" + columns(f.Fields, indent+"  ") + "\n" + indent + ">"
+	}
+
+	if f.Mode == "REPEATED" {
+		return "ARRAY<" + typ + ">"
+	}
+
+	return typ
+}
+
+// TableSink writes records as load-ready newline delimited JSON, see NewTableSink.
+type TableSink struct {
+	*JSONLinesSink
+	f *os.File
+}
+
+// NewTableSink writes the load files of a warehouse table into dir: <table>.schema.json
+// holding the BigQuery schema of record, <table>.sql its DDL, and <table>.ndjson the
+// rows written to the returned sink.
+func NewTableSink(dir, table string, record interface{}) (*TableSink, error) {
+	if err := writeSchema(dir, table, record); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(filepath.Join(dir, table+".ndjson"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TableSink{JSONLinesSink: NewJSONLinesSink(f), f: f}, nil
+}
+
+// writeSchema writes <table>.schema.json and <table>.sql for record into dir.
+func writeSchema(dir, table string, record interface{}) error {
+	fields := Schema(record)
+
+	schema, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Join(dir, table)
+	if err := ioutil.WriteFile(base+".schema.json", schema, 0644); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(base+".sql", []byte(DDL(table, fields)), 0644)
+}
+
+// Close flushes the rows and closes the file.
+func (s *TableSink) Close() error {
+	if err := s.JSONLinesSink.Close(); err != nil {
+		s.f.Close()
+		return err
+	}
+
+	return s.f.Close()
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestSchema(t *testing.T) {
+	fields := map[string]Field{}
+	for _, f := range Schema(&tenkft.Project{}) {
+		fields[f.Name] = f
+	}
+
+	expected := map[string]string{
+		"id":              "INT64",
+		"name":            "STRING",
+		"archived":        "BOOL",
+		"confirmed_hours": "FLOAT64",
+		"settings":        "JSON",
+		"tags":            "RECORD",
+	}
+	for name, typ := range expected {
+		if fields[name].Type != typ {
+			t.Errorf("expected %v to be %v, got %+v", name, typ, fields[name])
+		}
+	}
+
+	tags := fields["tags"].Fields
+	if len(tags) == 0 || tags[0].Name != "data" || tags[0].Mode != "REPEATED" {
+		t.Errorf("expected tags.data to be a repeated record, got %+v", tags)
+	}
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// JSONLinesSink writes every record as a line of JSON.
+type JSONLinesSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink returns a sink writing records to w as newline delimited JSON.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	bw := bufio.NewWriter(w)
+
+	return &JSONLinesSink{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Write encodes r as a line of JSON.
+func (s *JSONLinesSink) Write(ctx context.Context, r Record) error {
+	return s.enc.Encode(r)
+}
+
+// Close flushes buffered lines, it doesn't close the underlying writer.
+func (s *JSONLinesSink) Close() error {
+	return s.w.Flush()
+}
+
+// tee copies the lines written from now on to w.
+func (s *JSONLinesSink) tee(w io.Writer) {
+	s.enc = json.NewEncoder(io.MultiWriter(s.w, w))
+}
+
+// jsonLines returns s, letting wrappers reach the JSONLinesSink of the sinks embedding
+// it, such as TableSink and IncrementalSink.
+func (s *JSONLinesSink) jsonLines() *JSONLinesSink {
+	return s
+}
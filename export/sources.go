@@ -0,0 +1,75 @@
+package export
+
+import (
+	"context"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Send sends r to out unless ctx is canceled first, Sources should use it for every
+// record.
+func Send(ctx context.Context, out chan<- Record, r Record) error {
+	select {
+	case out <- r:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Projects returns a Source emitting every project matching opts. Pages are fetched as
+// the next stage takes the records, paginated like tenkft.Client.GetAllProjectsCtx.
+func Projects(c *tenkft.Client, opts map[string]string) Source {
+	return func(ctx context.Context, out chan<- Record) error {
+		ctx = recordPages(ctx, "/projects", opts)
+		projects, errs := c.StreamAllProjects(ctx, opts)
+
+		return forward(ctx, out, projects, errs)
+	}
+}
+
+// Users returns a Source emitting every user matching opts, see Projects.
+func Users(c *tenkft.Client, opts map[string]string) Source {
+	return func(ctx context.Context, out chan<- Record) error {
+		ctx = recordPages(ctx, "/users", opts)
+		users, errs := c.StreamAllUsers(ctx, opts)
+
+		return forward(ctx, out, users, errs)
+	}
+}
+
+// TimeEntries returns a Source emitting every time entry matching opts, see Projects.
+func TimeEntries(c *tenkft.Client, opts map[string]string) Source {
+	return func(ctx context.Context, out chan<- Record) error {
+		ctx = recordPages(ctx, "/time_entries", opts)
+		timeEntries, errs := c.StreamAllTimeEntries(ctx, opts)
+
+		return forward(ctx, out, timeEntries, errs)
+	}
+}
+
+// recordPages returns a copy of ctx recording the fetch of path with opts, and every
+// page of it, when the export keeps its provenance.
+func recordPages(ctx context.Context, path string, opts map[string]string) context.Context {
+	prov := provenance(ctx)
+	if prov == nil {
+		return ctx
+	}
+
+	recorded := prov.fetch(path, opts)
+
+	return tenkft.WithPageObserver(ctx, func(paging *tenkft.Paging, n int) {
+		prov.page(recorded, paging, n)
+	})
+}
+
+// forward sends the items of a stream to out and returns the error that ended it.
+func forward[T any](ctx context.Context, out chan<- Record, items <-chan T, errs <-chan error) error {
+	for item := range items {
+		if err := Send(ctx, out, item); err != nil {
+			return err
+		}
+	}
+
+	return <-errs
+}
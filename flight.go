@@ -0,0 +1,85 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// WithRequestCoalescing makes concurrent identical GETs, same URL and query parameters,
+// share a single upstream request. Followers get the leader's response and error,
+// unless the leader's context was canceled or timed out, in which case they make the
+// request again. Followers stop waiting when their own context is done.
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.flights = &flightGroup{calls: map[string]*flight{}}
+	}
+}
+
+// flight is a GET in progress, its result is shared by every caller waiting on it.
+type flight struct {
+	done chan struct{}
+	body []byte
+	resp *http.Response
+	err  error
+}
+
+// flightGroup coalesces concurrent calls with the same key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// do calls fetch unless a call for the same key is already in flight, in which case
+// it waits for that call and returns its result. The result of a call failing on its
+// context is not shared, the waiting callers calling fetch again.
+func (g *flightGroup) do(ctx context.Context, key string, fetch func() ([]byte, *http.Response, error)) ([]byte, *http.Response, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded) {
+			return g.do(ctx, key, fetch)
+		}
+		return f.body, f.resp, f.err
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.body, f.resp, f.err = fetch()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(f.done)
+
+	return f.body, f.resp, f.err
+}
+
+// flightKey identifies a GET to rawURL regardless of the order of its query parameters.
+func flightKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = u.Query().Encode()
+
+	return u.String()
+}
+
+// readAll reads and closes the body of resp.
+func readAll(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
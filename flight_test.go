@@ -0,0 +1,111 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestCoalescing(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		fmt.Fprint(w, `{"data": [{"id": 1, "name": "Engine"}], "paging": {"page": 1}}`)
+	}))
+	defer srv.Close()
+
+	client := &Client{token: "test", env: srv.URL}
+	WithRequestCoalescing()(client)
+
+	var wg sync.WaitGroup
+	projects := make([]*Projects, 5)
+	for i := range projects {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			projects[i], _, _ = client.GetProjects(map[string]string{"page": "1", "per_page": "10"})
+		}(i)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected a single upstream request, got %v", n)
+	}
+	for i, ps := range projects {
+		if ps == nil || len(ps.Data) != 1 || ps.Data[0].Name != "Engine" {
+			t.Errorf("expected caller %v to get the project, got %+v", i, ps)
+		}
+	}
+	if projects[0].Data[0] == projects[1].Data[0] {
+		t.Error("expected callers to get their own copy of the project")
+	}
+}
+
+func TestRequestCoalescingCanceled(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-release:
+			}
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1, "name": "Engine"}], "paging": {"page": 1}}`)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := &Client{token: "test", env: srv.URL}
+	WithRequestCoalescing()(client)
+	opts := map[string]string{"page": "1"}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, _, err := client.GetProjectsCtx(leaderCtx, opts)
+		leaderDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// A follower giving up doesn't wait for the leader.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.GetProjectsCtx(ctx, opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the follower to time out, got %v", err)
+	}
+
+	// A follower doesn't get the leader's cancellation.
+	followerDone := make(chan *Projects)
+	go func() {
+		projects, _, err := client.GetProjectsCtx(context.Background(), opts)
+		if err != nil {
+			t.Errorf("expected the follower to fetch again, got %v", err)
+		}
+		followerDone <- projects
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the leader to be canceled, got %v", err)
+	}
+	if projects := <-followerDone; projects == nil || len(projects.Data) != 1 {
+		t.Errorf("expected the follower to get the project, got %+v", projects)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected the follower to make its own request, got %v requests", n)
+	}
+}
@@ -0,0 +1,68 @@
+package tenkft
+
+// GroupByUser returns assignments keyed by user ID.
+func (as *Assignments) GroupByUser() map[UserID][]*Assignment {
+	groups := map[UserID][]*Assignment{}
+	for _, a := range as.Data {
+		groups[a.UserID] = append(groups[a.UserID], a)
+	}
+
+	return groups
+}
+
+// GroupByProject returns assignments keyed by the project they are on. Phase
+// assignments are under the ID of the phase and leave assignments under the ID of the
+// leave type, as both are assignables like projects.
+func (as *Assignments) GroupByProject() map[AssignableID][]*Assignment {
+	groups := map[AssignableID][]*Assignment{}
+	for _, a := range as.Data {
+		if a.baseAssignment == nil {
+			continue
+		}
+		groups[a.AssignableID] = append(groups[a.AssignableID], a)
+	}
+
+	return groups
+}
+
+// GroupByWeek returns assignments keyed by the first day of every week they overlap,
+// formatted as an API date, per the time zone and week start of c. Assignments with
+// unparsable dates are left out.
+func (as *Assignments) GroupByWeek(c *Client) map[string][]*Assignment {
+	groups := map[string][]*Assignment{}
+	for _, a := range as.Data {
+		if a.baseAssignment == nil {
+			continue
+		}
+
+		startsAt, err := c.ParseDate(a.StartsAt)
+		if err != nil {
+			continue
+		}
+		endsAt, err := c.ParseDate(a.EndsAt)
+		if err != nil {
+			continue
+		}
+
+		for week, _ := c.Week(startsAt); !week.After(endsAt); week = week.AddDate(0, 0, 7) {
+			key := c.FormatDate(week)
+			groups[key] = append(groups[key], a)
+		}
+	}
+
+	return groups
+}
+
+// GroupByDiscipline returns users keyed by discipline, "" for users without one.
+func (users *Users) GroupByDiscipline() map[string][]*User {
+	groups := map[string][]*User{}
+	for _, u := range users.Data {
+		discipline := ""
+		if u.baseUser != nil {
+			discipline = u.Discipline
+		}
+		groups[discipline] = append(groups[discipline], u)
+	}
+
+	return groups
+}
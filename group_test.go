@@ -0,0 +1,36 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGroupBy(t *testing.T) {
+	c := &Client{weekStart: time.Monday}
+
+	var assignments Assignments
+	json.Unmarshal([]byte(`{"data": [
+		{"id": 1, "user_id": 1, "assignable_id": 7, "starts_at": "2018-03-07", "ends_at": "2018-03-13"},
+		{"id": 2, "user_id": 2, "assignable_id": 7, "starts_at": "2018-03-12", "ends_at": "2018-03-12"},
+		{"id": 3, "user_id": 1, "assignable_id": 8, "starts_at": "2018-03-19", "ends_at": "bogus"}
+	]}`), &assignments)
+
+	if byUser := assignments.GroupByUser(); len(byUser[1]) != 2 || len(byUser[2]) != 1 {
+		t.Errorf("expected 2 assignments for user 1 and 1 for user 2, got %v", byUser)
+	}
+	if byProject := assignments.GroupByProject(); len(byProject[7]) != 2 || len(byProject[8]) != 1 {
+		t.Errorf("expected 2 assignments on project 7 and 1 on 8, got %v", byProject)
+	}
+
+	byWeek := assignments.GroupByWeek(c)
+	if len(byWeek) != 2 || len(byWeek["2018-03-05"]) != 1 || len(byWeek["2018-03-12"]) != 2 {
+		t.Errorf("expected assignment 1 in both weeks and 2 in the second, got %v", byWeek)
+	}
+
+	var users Users
+	json.Unmarshal([]byte(`{"data": [{"id": 1, "discipline": "Design"}, {"id": 2, "discipline": "Design"}, {"id": 3}]}`), &users)
+	if byDiscipline := users.GroupByDiscipline(); len(byDiscipline["Design"]) != 2 || len(byDiscipline[""]) != 1 {
+		t.Errorf("expected 2 designers and 1 user without discipline, got %v", byDiscipline)
+	}
+}
@@ -0,0 +1,17 @@
+package tenkft
+
+// UserID, ProjectID, AssignmentID and LeaveTypeID are the IDs of users, projects,
+// assignments and leave types. They are distinct types so that passing a user ID where
+// a project ID is expected doesn't compile. Phases being projects, their IDs are
+// ProjectIDs.
+type (
+	UserID       int
+	ProjectID    int
+	AssignmentID int
+	LeaveTypeID  int
+)
+
+// AssignableID is the ID of what assignments, time entries, bill rates, budget and
+// expense items are on: a project, a phase or a leave type, whose IDs convert to it,
+// e.g. AssignableID(p.ID).
+type AssignableID int
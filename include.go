@@ -0,0 +1,176 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Inclusion is a sub-resource embedded in user or project responses, build them with
+// the Nested* functions and render them with Include.
+type Inclusion struct {
+	field  string
+	params map[string]string
+}
+
+// InclusionParam narrows down what an Inclusion embeds, e.g. From and To.
+type InclusionParam func(*Inclusion)
+
+// From limits embedded records to those ending on or after day.
+func From(day time.Time) InclusionParam {
+	return func(i *Inclusion) {
+		i.params["from"] = day.Format(DateFormat)
+	}
+}
+
+// To limits embedded records to those starting on or before day.
+func To(day time.Time) InclusionParam {
+	return func(i *Inclusion) {
+		i.params["to"] = day.Format(DateFormat)
+	}
+}
+
+// NestedAssignments embeds assignments, decoded into the Assignments field.
+func NestedAssignments(params ...InclusionParam) Inclusion {
+	return newInclusion("assignments", params)
+}
+
+// NestedAvailabilities embeds availabilities, decoded into User.Availabilities.
+func NestedAvailabilities(params ...InclusionParam) Inclusion {
+	return newInclusion("availabilities", params)
+}
+
+// NestedTags embeds tags, decoded into the Tags field.
+func NestedTags() Inclusion {
+	return newInclusion("tags", nil)
+}
+
+// NestedCustomFieldValues embeds custom field values, decoded into the
+// CustomFieldValues field.
+func NestedCustomFieldValues() Inclusion {
+	return newInclusion("custom_field_values", nil)
+}
+
+func newInclusion(field string, params []InclusionParam) Inclusion {
+	i := Inclusion{field: field, params: map[string]string{}}
+	for _, param := range params {
+		param(&i)
+	}
+
+	return i
+}
+
+// Include renders inclusions into the query options understood by GetUsers,
+// GetProjects and friends:
+//
+//	opts := tenkft.Include(tenkft.NestedAssignments(tenkft.From(d1), tenkft.To(d2)), tenkft.NestedTags())
+//	opts["per_page"] = "100"
+//	users, _, err := c.GetUsers(opts)
+//
+// The API takes a single date window, so when several inclusions set From or To the
+// last one wins.
+func Include(inclusions ...Inclusion) map[string]string {
+	opts := map[string]string{}
+	fields := []string{}
+	for _, i := range inclusions {
+		fields = append(fields, i.field)
+		for k, v := range i.params {
+			opts[k] = v
+		}
+	}
+
+	if len(fields) > 0 {
+		opts["fields"] = strings.Join(fields, ",")
+	}
+
+	return opts
+}
+
+// GetAllUsersWithAssignments returns every user matching opts with their assignments
+// between from and to embedded in their Assignments field, in place of one
+// GetAllUserAssignments call per user. The few users whose embedded assignments don't
+// fit in a single page get theirs fetched separately.
+func (c *Client) GetAllUsersWithAssignments(from, to time.Time, opts map[string]string) (*Users, *http.Response, error) {
+	return c.GetAllUsersWithAssignmentsCtx(context.Background(), from, to, opts)
+}
+
+// GetAllUsersWithAssignmentsCtx is GetAllUsersWithAssignments honouring ctx.
+func (c *Client) GetAllUsersWithAssignmentsCtx(ctx context.Context, from, to time.Time, opts map[string]string) (users *Users, resp *http.Response, err error) {
+	users, resp, err = usersEndpoint.ListAll(ctx, c, withInclusions(opts, NestedAssignments(From(from), To(to))))
+	if err != nil {
+		return
+	}
+
+	for _, u := range users.Data {
+		resp, err = c.completeAssignments(ctx, &u.Assignments, userAssignmentsEndpoint, from, to, int(u.ID))
+		if err != nil {
+			return
+		}
+	}
+
+	err = c.enrich(ctx, users.Data...)
+
+	return
+}
+
+// GetAllProjectsWithAssignments returns every project matching opts with their
+// assignments between from and to embedded, see GetAllUsersWithAssignments.
+func (c *Client) GetAllProjectsWithAssignments(from, to time.Time, opts map[string]string) (*Projects, *http.Response, error) {
+	return c.GetAllProjectsWithAssignmentsCtx(context.Background(), from, to, opts)
+}
+
+// GetAllProjectsWithAssignmentsCtx is GetAllProjectsWithAssignments honouring ctx.
+func (c *Client) GetAllProjectsWithAssignmentsCtx(ctx context.Context, from, to time.Time, opts map[string]string) (projects *Projects, resp *http.Response, err error) {
+	projects, resp, err = projectsEndpoint.ListAll(ctx, c, withInclusions(opts, NestedAssignments(From(from), To(to))))
+	if err != nil {
+		return
+	}
+
+	for _, p := range projects.Data {
+		resp, err = c.completeAssignments(ctx, &p.Assignments, projectAssignmentsEndpoint, from, to, int(p.ID))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// completeAssignments refetches embedded assignments through e when they were cut
+// short by pagination, and expands them.
+func (c *Client) completeAssignments(ctx context.Context, assignments *Assignments, e Endpoint[*Assignments, *Assignment], from, to time.Time, parentID int) (resp *http.Response, err error) {
+	if assignments.Paging != nil && assignments.Paging.HasNext() {
+		opts := Include(NestedAssignments(From(from), To(to)))
+		delete(opts, "fields")
+
+		var all *Assignments
+		all, resp, err = e.ListAll(ctx, c, opts, parentID)
+		if err != nil {
+			return
+		}
+		*assignments = *all
+	}
+
+	err = c.expand(assignments)
+
+	return
+}
+
+// withInclusions returns a copy of opts with inclusions added to the fields it
+// already asks for.
+func withInclusions(opts map[string]string, inclusions ...Inclusion) map[string]string {
+	query := map[string]string{}
+	for k, v := range opts {
+		query[k] = v
+	}
+
+	for k, v := range Include(inclusions...) {
+		if k == "fields" && query[k] != "" {
+			v = query[k] + "," + v
+		}
+		query[k] = v
+	}
+
+	return query
+}
@@ -0,0 +1,63 @@
+package tenkft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInclude(t *testing.T) {
+	d1 := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2018, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	opts := Include(NestedAssignments(From(d1), To(d2)), NestedTags(), NestedCustomFieldValues())
+	expected := map[string]string{
+		"fields": "assignments,tags,custom_field_values",
+		"from":   "2018-03-01",
+		"to":     "2018-03-31",
+	}
+	for k, v := range expected {
+		if opts[k] != v {
+			t.Errorf("expected %v=%v, got %v", k, v, opts[k])
+		}
+	}
+	if len(opts) != len(expected) {
+		t.Errorf("expected %v params, got %v", len(expected), opts)
+	}
+}
+
+func TestGetAllUsersWithAssignments(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/users": `{"data": [
+			{"id": 1, "assignments": {"data": [{"id": 10, "user_id": 1}], "paging": {"page": 1, "next": null}}},
+			{"id": 2, "assignments": {"data": [{"id": 20, "user_id": 2}], "paging": {"page": 1, "next": "/users/2/assignments?page=2"}}}
+		], "paging": {"page": 1, "next": null}}`,
+		"/users/2/assignments": `{"data": [{"id": 20, "user_id": 2}, {"id": 21, "user_id": 2}], "paging": {"page": 1, "next": null}}`,
+	})
+
+	d := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	users, _, err := client.GetAllUsersWithAssignments(d, d.AddDate(0, 1, 0), map[string]string{"fields": "tags"})
+	if err != nil {
+		t.Fatal("could not get users with assignments", err)
+	}
+
+	if len(users.Data) != 2 {
+		t.Fatalf("expected 2 users, got %v", len(users.Data))
+	}
+	if n := len(users.Data[0].Assignments.Data); n != 1 || users.Data[0].Assignments.Data[0].ID != 10 {
+		t.Errorf("expected the embedded assignment of user 1, got %v", n)
+	}
+	if n := len(users.Data[1].Assignments.Data); n != 2 {
+		t.Errorf("expected the paginated assignments of user 2 to be refetched, got %v", n)
+	}
+}
+
+func TestWithInclusions(t *testing.T) {
+	opts := map[string]string{"fields": "tags"}
+	query := withInclusions(opts, NestedAssignments())
+	if query["fields"] != "tags,assignments" {
+		t.Errorf("expected fields to be merged, got %v", query["fields"])
+	}
+	if opts["fields"] != "tags" {
+		t.Errorf("expected opts not to be modified, got %v", opts)
+	}
+}
@@ -0,0 +1,82 @@
+package tenkft
+
+import "sync"
+
+// nameIndex maps the names of a collection's items to the first item bearing each. It
+// notices Data being replaced or appended to by its length and first element, in O(1);
+// items replaced or renamed in place need a reindex, see Tags.Reindex.
+type nameIndex[T any] struct {
+	mu     sync.RWMutex
+	first  *T
+	n      int
+	byName map[string]T
+}
+
+// indexesMu guards the allocation of the collections' indexes, each index then has its
+// own lock.
+var indexesMu sync.RWMutex
+
+// indexOf returns the index at *idx, allocating it on first use.
+func indexOf[T any](idx **nameIndex[T]) *nameIndex[T] {
+	indexesMu.RLock()
+	index := *idx
+	indexesMu.RUnlock()
+	if index != nil {
+		return index
+	}
+
+	indexesMu.Lock()
+	defer indexesMu.Unlock()
+	if *idx == nil {
+		*idx = &nameIndex[T]{}
+	}
+
+	return *idx
+}
+
+// findByName looks name up in the index at *idx, building it from data first when it
+// is missing or out of date.
+func findByName[T any](idx **nameIndex[T], data []T, name string, nameOf func(T) string) T {
+	index := indexOf(idx)
+
+	index.mu.RLock()
+	if index.matches(data) {
+		defer index.mu.RUnlock()
+		return index.byName[name]
+	}
+	index.mu.RUnlock()
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if !index.matches(data) {
+		index.byName = make(map[string]T, len(data))
+		for _, item := range data {
+			if _, ok := index.byName[nameOf(item)]; !ok {
+				index.byName[nameOf(item)] = item
+			}
+		}
+		index.n, index.first = len(data), nil
+		if len(data) > 0 {
+			index.first = &data[0]
+		}
+	}
+
+	return index.byName[name]
+}
+
+// reindex makes the next lookup rebuild the index at *idx.
+func reindex[T any](idx **nameIndex[T]) {
+	index := indexOf(idx)
+
+	index.mu.Lock()
+	index.byName = nil
+	index.mu.Unlock()
+}
+
+func (idx *nameIndex[T]) matches(data []T) bool {
+	if idx.byName == nil || idx.n != len(data) {
+		return false
+	}
+
+	return len(data) == 0 || idx.first == &data[0]
+}
@@ -0,0 +1,49 @@
+package tenkft
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFindByName(t *testing.T) {
+	lts := &LeaveTypes{Data: []*LeaveType{{ID: 1, Name: "Vacation"}, {ID: 2, Name: "Sick"}, {ID: 3, Name: "Vacation"}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lt := lts.FindByName("Vacation"); lt == nil || lt.ID != 1 {
+				t.Errorf("expected the first vacation leave type, got %+v", lt)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if lt := lts.FindByName("Parental"); lt != nil {
+		t.Errorf("expected nil for an unknown name, got %+v", lt)
+	}
+
+	lts.Data = append(lts.Data, &LeaveType{ID: 4, Name: "Parental"})
+	if lt := lts.FindByName("Parental"); lt == nil || lt.ID != 4 {
+		t.Errorf("expected the index to be rebuilt after Data changed, got %+v", lt)
+	}
+
+	// Items replaced in place need a reindex.
+	lts.Data[0] = &LeaveType{ID: 5, Name: "Vacation"}
+	lts.Reindex()
+	if lt := lts.FindByName("Vacation"); lt == nil || lt.ID != 5 {
+		t.Errorf("expected the index to be rebuilt by Reindex, got %+v", lt)
+	}
+
+	// Same length, different backing array sharing the first item.
+	lts.Data = []*LeaveType{lts.Data[0], {ID: 6, Name: "Sick"}, lts.Data[2], lts.Data[3]}
+	if lt := lts.FindByName("Sick"); lt == nil || lt.ID != 6 {
+		t.Errorf("expected the index to be rebuilt after Data was replaced, got %+v", lt)
+	}
+
+	roles := &Roles{Data: []*Role{{ID: 5, Value: "Designer"}}}
+	if r := roles.FindByName("Designer"); r == nil || r.ID != 5 {
+		t.Errorf("expected the designer role, got %+v", r)
+	}
+}
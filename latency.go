@@ -0,0 +1,45 @@
+package tenkft
+
+import "time"
+
+// LatencyHook is called after every API call that took longer than the budget of its
+// endpoint, e.g. "/users/%d/assignments", to log a warning or count it in a metric.
+// took excludes the backoff between retries, which RetryHook reports.
+type LatencyHook func(method, endpoint string, took, budget time.Duration)
+
+// latencyBudgets holds the budgets set by WithLatencyBudgets.
+type latencyBudgets struct {
+	budgets map[string]time.Duration
+	hook    LatencyHook
+}
+
+// WithLatencyBudgets sets how long calls to each endpoint, named as by RetryHook, are
+// expected to take, hook being called for the calls going over. The budget of the
+// endpoint "" applies to the endpoints not listed, a zero budget disables the check.
+//
+//	tenkft.WithLatencyBudgets(map[string]time.Duration{
+//		"":                      2 * time.Second,
+//		"/users/%d/assignments": 5 * time.Second,
+//	}, func(method, endpoint string, took, budget time.Duration) {
+//		log.Printf("%v %v took %v, expected %v", method, endpoint, took, budget)
+//	})
+func WithLatencyBudgets(budgets map[string]time.Duration, hook LatencyHook) ClientOption {
+	return func(c *Client) {
+		c.latency = latencyBudgets{budgets: budgets, hook: hook}
+	}
+}
+
+// check calls the hook when took is over the budget of endpoint.
+func (l latencyBudgets) check(method, endpoint string, took time.Duration) {
+	if l.hook == nil {
+		return
+	}
+
+	budget, ok := l.budgets[endpoint]
+	if !ok {
+		budget, ok = l.budgets[""]
+	}
+	if ok && budget > 0 && took > budget {
+		l.hook(method, endpoint, took, budget)
+	}
+}
@@ -0,0 +1,40 @@
+package tenkft
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLatencyBudgets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/projects/42" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		fmt.Fprint(w, `{"data": [], "paging": {}}`)
+	}))
+	defer srv.Close()
+
+	over := []string{}
+	client := &Client{token: "test", env: srv.URL}
+	WithLatencyBudgets(map[string]time.Duration{
+		"":          time.Millisecond,
+		"/projects": time.Hour,
+		"/users/%d": 0,
+	}, func(method, endpoint string, took, budget time.Duration) {
+		if took <= budget {
+			t.Errorf("%v: %v is within %v", endpoint, took, budget)
+		}
+		over = append(over, method+" "+endpoint)
+	})(client)
+
+	client.GetProjects(map[string]string{})
+	client.GetProjectByID(42, map[string]string{})
+	client.GetUser(&User{ID: 1}, map[string]string{})
+
+	if len(over) != 1 || over[0] != "GET /projects/%d" {
+		t.Errorf("expected only the slow project call to be reported, got %v", over)
+	}
+}
@@ -0,0 +1,51 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLimitExceeded is returned by GetAll* methods when a collection has more pages or
+// records than the Limits in effect allow, along with what was fetched within them.
+var ErrLimitExceeded = errors.New("tenkft: pagination limit exceeded")
+
+// Limits caps what GetAll* methods accumulate so that a mis-filtered call cannot pull
+// the whole account into memory. Zero fields mean no limit.
+type Limits struct {
+	// MaxPages is the number of pages fetched at most.
+	MaxPages int
+	// MaxRecords is the number of records returned at most.
+	MaxRecords int
+}
+
+// limitsKey is the context key per call Limits are stored under.
+type limitsKey struct{}
+
+// WithMaxPages caps the pages fetched by GetAll* methods, see Limits.
+func WithMaxPages(n int) ClientOption {
+	return func(c *Client) {
+		c.limits.MaxPages = n
+	}
+}
+
+// WithMaxRecords caps the records returned by GetAll* methods, see Limits.
+func WithMaxRecords(n int) ClientOption {
+	return func(c *Client) {
+		c.limits.MaxRecords = n
+	}
+}
+
+// WithLimits returns a copy of ctx making the GetAll*Ctx methods called with it apply
+// limits instead of the client's, e.g. to lift them for a known large export.
+func WithLimits(ctx context.Context, limits Limits) context.Context {
+	return context.WithValue(ctx, limitsKey{}, limits)
+}
+
+// Limits returns the limits GetAll*Ctx methods apply when called with ctx.
+func (c *Client) Limits(ctx context.Context) Limits {
+	if limits, ok := ctx.Value(limitsKey{}).(Limits); ok {
+		return limits
+	}
+
+	return c.limits
+}
@@ -0,0 +1,38 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		fmt.Fprintf(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": %s, "next": "/users?page=next"}}`, page)
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient("test", Production, WithMaxPages(3))
+	client.env = srv.URL
+
+	users, _, err := client.GetAllUsers(map[string]string{})
+	if !errors.Is(err, ErrLimitExceeded) || len(users.Data) != 6 {
+		t.Errorf("expected 3 pages and ErrLimitExceeded, got %v users and %v", len(users.Data), err)
+	}
+
+	ctx := WithLimits(context.Background(), Limits{MaxRecords: 3})
+	users, _, err = client.GetAllUsersCtx(ctx, map[string]string{})
+	if !errors.Is(err, ErrLimitExceeded) || len(users.Data) != 3 {
+		t.Errorf("expected 3 users and ErrLimitExceeded, got %v users and %v", len(users.Data), err)
+	}
+	if client.Limits(ctx).MaxPages != 0 || client.Limits(context.Background()).MaxPages != 3 {
+		t.Error("expected per call limits to replace the client's")
+	}
+}
@@ -0,0 +1,191 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency assumed when none was configured with WithCurrency.
+// The API doesn't report the account currency.
+const DefaultCurrency = "USD"
+
+// minorUnits are the decimals of currencies that don't use 2.
+var minorUnits = map[string]int{"JPY": 0, "KRW": 0, "CLP": 0, "ISK": 0, "VND": 0, "BHD": 3, "KWD": 3, "OMR": 3}
+
+// WithCurrency sets the ISO 4217 code of the account currency, used by Money.
+func WithCurrency(currency string) ClientOption {
+	return func(c *Client) {
+		c.currency = strings.ToUpper(currency)
+	}
+}
+
+// Currency returns the account currency, DefaultCurrency unless WithCurrency was used.
+func (c *Client) Currency() string {
+	if c.currency == "" {
+		return DefaultCurrency
+	}
+
+	return c.currency
+}
+
+// Money converts a dollar field of the API into Money in the account currency.
+func (c *Client) Money(amount float64) Money {
+	return NewMoney(amount, c.Currency())
+}
+
+// Money is an amount held in the minor unit of its currency, e.g. cents, so that sums
+// of many amounts are exact.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// NewMoney rounds amount to the nearest minor unit of currency.
+func NewMoney(amount float64, currency string) Money {
+	return Money{Minor: int64(math.Round(amount * math.Pow10(decimals(currency)))), Currency: currency}
+}
+
+func decimals(currency string) int {
+	if d, ok := minorUnits[currency]; ok {
+		return d
+	}
+
+	return 2
+}
+
+// Add returns m + o, both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Minor: m.Minor + o.Minor, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o, both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Minor: m.Minor - o.Minor, Currency: m.Currency}, nil
+}
+
+// Mul returns m times f rounded to the nearest minor unit, e.g. a rate times hours.
+func (m Money) Mul(f float64) Money {
+	return Money{Minor: int64(math.Round(float64(m.Minor) * f)), Currency: m.Currency}
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("cannot combine %v and %v amounts", m.Currency, o.Currency)
+	}
+
+	return nil
+}
+
+// Float64 returns the amount in major units, for display and ratios only.
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / math.Pow10(decimals(m.Currency))
+}
+
+// Amount formats the amount in major units without currency, e.g. "-1234.50".
+func (m Money) Amount() string {
+	d := decimals(m.Currency)
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+
+	if d == 0 {
+		return sign + strconv.FormatInt(minor, 10)
+	}
+
+	unit := int64(math.Pow10(d))
+
+	return fmt.Sprintf("%v%d.%0*d", sign, minor/unit, d, minor%unit)
+}
+
+// String formats m as "1234.50 USD".
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency
+}
+
+// MarshalJSON encodes m as {"amount": "1234.50", "currency": "USD"}, keeping the
+// amount a string so that it isn't turned back into a float by consumers.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Amount(), m.Currency})
+}
+
+// ProjectFinancials are the dollar fields of a project as Money.
+type ProjectFinancials struct {
+	Confirmed   Money `json:"confirmed"`
+	Approved    Money `json:"approved"`
+	Unconfirmed Money `json:"unconfirmed"`
+	Scheduled   Money `json:"scheduled"`
+	Future      Money `json:"future"`
+}
+
+// ProjectFinancials returns the dollar fields of p in the account currency.
+func (c *Client) ProjectFinancials(p *Project) ProjectFinancials {
+	return ProjectFinancials{
+		Confirmed:   c.Money(p.ConfirmedDollars),
+		Approved:    c.Money(p.ApprovedDollars),
+		Unconfirmed: c.Money(p.UnconfirmedDollars),
+		Scheduled:   c.Money(p.ScheduledDollars),
+		Future:      c.Money(p.FutureDollars),
+	}
+}
+
+// SumMoney adds up amounts exactly, all must be in the same currency. The sum of no
+// amounts is zero in DefaultCurrency.
+func SumMoney(amounts ...Money) (total Money, err error) {
+	if len(amounts) == 0 {
+		return Money{Currency: DefaultCurrency}, nil
+	}
+
+	total = Money{Currency: amounts[0].Currency}
+	for _, m := range amounts {
+		total, err = total.Add(m)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+
+	return
+}
+
+// TotalProjectFinancials sums the financials of every project, rounding each project's
+// figures to the minor unit before adding them so totals match per-project reports.
+func (c *Client) TotalProjectFinancials(projects *Projects) ProjectFinancials {
+	zero := c.Money(0)
+	total := ProjectFinancials{zero, zero, zero, zero, zero}
+	for _, p := range projects.Data {
+		f := c.ProjectFinancials(p)
+		total.Confirmed.Minor += f.Confirmed.Minor
+		total.Approved.Minor += f.Approved.Minor
+		total.Unconfirmed.Minor += f.Unconfirmed.Minor
+		total.Scheduled.Minor += f.Scheduled.Minor
+		total.Future.Minor += f.Future.Minor
+	}
+
+	return total
+}
+
+// TimeEntriesCost returns the billable value of time entries, hours times bill rate,
+// with every entry rounded to the minor unit before being added up.
+func (c *Client) TimeEntriesCost(timeEntries *TimeEntries) Money {
+	total := c.Money(0)
+	for _, te := range timeEntries.Data {
+		total.Minor += c.Money(te.BillRate).Mul(te.Hours).Minor
+	}
+
+	return total
+}
@@ -0,0 +1,38 @@
+package tenkft
+
+import "testing"
+
+func TestMoneySumIsExact(t *testing.T) {
+	total := NewMoney(0, "USD")
+	for i := 0; i < 1000; i++ {
+		total, _ = total.Add(NewMoney(0.1, "USD"))
+	}
+
+	if total.String() != "100.00 USD" {
+		t.Errorf("expected 100.00 USD, got %v", total)
+	}
+
+	if _, err := total.Add(NewMoney(1, "EUR")); err == nil {
+		t.Error("expected adding different currencies to fail")
+	}
+
+	if s := NewMoney(-1234.5, "USD").Amount(); s != "-1234.50" {
+		t.Errorf("expected -1234.50, got %v", s)
+	}
+	if s := NewMoney(1234.5, "JPY").String(); s != "1235 JPY" {
+		t.Errorf("expected 1235 JPY, got %v", s)
+	}
+}
+
+func TestTimeEntriesCost(t *testing.T) {
+	c := &Client{}
+	timeEntries := &TimeEntries{}
+	for i := 0; i < 3000; i++ {
+		timeEntries.Data = append(timeEntries.Data, &TimeEntry{Hours: 0.1, BillRate: 33.33})
+	}
+
+	// every entry is worth 3.333, rounded to 3.33
+	if cost := c.TimeEntriesCost(timeEntries); cost.String() != "9990.00 USD" {
+		t.Errorf("expected 9990.00 USD, got %v", cost)
+	}
+}
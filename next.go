@@ -0,0 +1,124 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ErrNoNextPage is returned when following the Next URL of the last page.
+var ErrNoNextPage = errors.New("tenkft: no next page")
+
+// Collections nested in other responses, only fetched through FollowNext.
+var (
+	availabilitiesEndpoint = Endpoint[*Availabilities, *Availability]{
+		Path: "/users/%d/availabilities",
+		Wrap: func(data []*Availability, paging *Paging) *Availabilities {
+			return &Availabilities{Data: data, Paging: paging}
+		},
+	}
+	placeholderResourcesEndpoint = Endpoint[*PlaceholderResources, *PlaceholderResource]{
+		Path: "/placeholder_resources",
+		Wrap: func(data []*PlaceholderResource, paging *Paging) *PlaceholderResources {
+			return &PlaceholderResources{Data: data, Paging: paging}
+		},
+	}
+)
+
+// resolve returns the absolute URL of ref, a URL returned by the API which may be
+// relative to the environment.
+func (c *Client) resolve(ref string) (string, error) {
+	base, err := url.Parse(c.env)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(u).String(), nil
+}
+
+// FollowNext fetches the page following ps, requesting the Next URL returned by the API so
+// that its page size and filters are kept. err is ErrNoNextPage on the last page.
+func (ps *Projects) FollowNext(ctx context.Context, c *Client) (*Projects, *http.Response, error) {
+	return projectsEndpoint.Next(ctx, c, ps.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (users *Users) FollowNext(ctx context.Context, c *Client) (*Users, *http.Response, error) {
+	return usersEndpoint.Next(ctx, c, users.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (as *Assignments) FollowNext(ctx context.Context, c *Client) (*Assignments, *http.Response, error) {
+	return userAssignmentsEndpoint.Next(ctx, c, as.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (p *Phases) FollowNext(ctx context.Context, c *Client) (*Phases, *http.Response, error) {
+	return phasesEndpoint.Next(ctx, c, p.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (ts *Tags) FollowNext(ctx context.Context, c *Client) (*Tags, *http.Response, error) {
+	return userTagsEndpoint.Next(ctx, c, ts.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (a *Availabilities) FollowNext(ctx context.Context, c *Client) (*Availabilities, *http.Response, error) {
+	return availabilitiesEndpoint.Next(ctx, c, a.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (p *PlaceholderResources) FollowNext(ctx context.Context, c *Client) (*PlaceholderResources, *http.Response, error) {
+	return placeholderResourcesEndpoint.Next(ctx, c, p.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (l *LeaveTypes) FollowNext(ctx context.Context, c *Client) (*LeaveTypes, *http.Response, error) {
+	return leaveTypesEndpoint.Next(ctx, c, l.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (r *Roles) FollowNext(ctx context.Context, c *Client) (*Roles, *http.Response, error) {
+	return rolesEndpoint.Next(ctx, c, r.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (b *BillRates) FollowNext(ctx context.Context, c *Client) (*BillRates, *http.Response, error) {
+	return projectBillRatesEndpoint.Next(ctx, c, b.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (t *TimeEntries) FollowNext(ctx context.Context, c *Client) (*TimeEntries, *http.Response, error) {
+	return timeEntriesEndpoint.Next(ctx, c, t.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (b *BudgetItems) FollowNext(ctx context.Context, c *Client) (*BudgetItems, *http.Response, error) {
+	return budgetItemsEndpoint.Next(ctx, c, b.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (e *ExpenseItems) FollowNext(ctx context.Context, c *Client) (*ExpenseItems, *http.Response, error) {
+	return userExpenseItemsEndpoint.Next(ctx, c, e.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (h *Holidays) FollowNext(ctx context.Context, c *Client) (*Holidays, *http.Response, error) {
+	return holidaysEndpoint.Next(ctx, c, h.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (a *Approvals) FollowNext(ctx context.Context, c *Client) (*Approvals, *http.Response, error) {
+	return approvalsEndpoint.Next(ctx, c, a.Paging)
+}
+
+// FollowNext fetches the next page, see Projects.FollowNext.
+func (d *Disciplines) FollowNext(ctx context.Context, c *Client) (*Disciplines, *http.Response, error) {
+	return disciplinesEndpoint.Next(ctx, c, d.Paging)
+}
@@ -0,0 +1,83 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPagingNext(t *testing.T) {
+	p := &Paging{Page: 1, Next: "/api/v1/projects?page=3&per_page=20&with_archived=true"}
+	if p.GetNextPage() != 3 {
+		t.Errorf("expected the page of the Next URL, got %v", p.GetNextPage())
+	}
+
+	params, ok := p.NextParams()
+	if !ok || params["per_page"] != "20" || params["with_archived"] != "true" {
+		t.Errorf("unexpected next params %v", params)
+	}
+
+	p.Next = "null"
+	if _, ok := p.NextParams(); ok || p.GetNextPage() != 2 {
+		t.Errorf("expected no next params and page 2, got %v", p.GetNextPage())
+	}
+}
+
+func TestFollowNext(t *testing.T) {
+	requested := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"data": [{"id": 2}], "paging": {"page": 2, "next": null}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1}], "paging": {"page": 1, "next": "/projects?per_page=1&page=2&with_archived=true"}}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	projects, _, err := client.GetProjects(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, _, err := projects.FollowNext(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Data) != 1 || next.Data[0].ID != 2 {
+		t.Errorf("expected project 2, got %+v", next.Data)
+	}
+	if requested[1] != "/projects?per_page=1&page=2&with_archived=true" {
+		t.Errorf("expected the Next URL to be requested as is, got %v", requested[1])
+	}
+
+	if _, _, err := next.FollowNext(context.Background(), client); err != ErrNoNextPage {
+		t.Errorf("expected ErrNoNextPage, got %v", err)
+	}
+
+	requested = requested[:0]
+	if _, _, err := client.GetAllProjects(map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requested) != 2 {
+		t.Fatalf("expected 2 pages, got %v", requested)
+	}
+	if q := query(t, requested[1]); q.Get("with_archived") != "true" || q.Get("per_page") != "1" || q.Get("page") != "2" {
+		t.Errorf("expected ListAll to follow the filters of the Next URL, got %v", requested[1])
+	}
+}
+
+func query(t *testing.T, uri string) url.Values {
+	t.Helper()
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u.Query()
+}
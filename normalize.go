@@ -0,0 +1,63 @@
+package tenkft
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// The API spells some fields differently depending on the resource, e.g. displayName on
+// placeholder resources but display_name on users. These map the spellings a type may
+// receive to the one its field is tagged with, so that equivalent fields are populated
+// whichever spelling was sent.
+var (
+	userAliases = map[string]string{
+		"displayName": "display_name",
+		"bill_rate":   "billrate",
+	}
+	placeholderAliases = map[string]string{
+		"display_name": "displayName",
+		"bill_rate":    "billrate",
+	}
+	timeEntryAliases = map[string]string{
+		"billrate": "bill_rate",
+	}
+)
+
+// normalize rewrites the keys of the JSON object data found in aliases to their
+// canonical spelling. A canonical key already present wins over its aliases. data is
+// returned as is when it holds none of them or isn't an object.
+func normalize(data []byte, aliases map[string]string) []byte {
+	found := false
+	for alias := range aliases {
+		if bytes.Contains(data, []byte(`"`+alias+`"`)) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return data
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return data
+	}
+
+	for alias, canonical := range aliases {
+		v, ok := fields[alias]
+		if !ok {
+			continue
+		}
+		if _, ok := fields[canonical]; !ok {
+			fields[canonical] = v
+		}
+		delete(fields, alias)
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+
+	return normalized
+}
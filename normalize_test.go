@@ -0,0 +1,32 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	var u User
+	if err := json.Unmarshal([]byte(`{"id": 1, "displayName": "Ada Lovelace", "bill_rate": 150, "first_name": "Ada"}`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.DisplayName != "Ada Lovelace" || u.Billrate != 150 || u.FirstName != "Ada" {
+		t.Errorf("expected aliased user fields to be populated, got %+v", u)
+	}
+
+	var pr PlaceholderResource
+	if err := json.Unmarshal([]byte(`{"id": 2, "display_name": "Designer", "displayName": "Senior Designer"}`), &pr); err != nil {
+		t.Fatal(err)
+	}
+	if pr.DisplayName != "Senior Designer" {
+		t.Errorf("expected the canonical spelling to win, got %q", pr.DisplayName)
+	}
+
+	var te TimeEntry
+	if err := json.Unmarshal([]byte(`{"id": 3, "billrate": 90}`), &te); err != nil {
+		t.Fatal(err)
+	}
+	if te.BillRate != 90 || te.ID != 3 {
+		t.Errorf("expected billrate to populate BillRate, got %+v", te)
+	}
+}
@@ -0,0 +1,231 @@
+package tenkft
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions are the query parameters every list call understands. The option structs
+// render to the query options taken by GetProjects, GetAllUsers and friends with Params:
+//
+//	opts := tenkft.ProjectListOptions{WithArchived: true, ListOptions: tenkft.ListOptions{Fields: []string{"tags"}}}
+//	projects, _, err := c.GetAllProjects(opts.Params())
+type ListOptions struct {
+	// Fields lists the sub-resources embedded in the items, e.g. "tags", see Include.
+	Fields []string
+	// Page and PerPage are left to the API when zero, GetAll* methods paginate on their
+	// own.
+	Page    int
+	PerPage int
+	// RawParams are sent as they are, overriding the parameters set by the other fields,
+	// for the ones the structs don't cover.
+	RawParams map[string]string
+}
+
+// Values renders o as URL query values.
+func (o ListOptions) Values() url.Values {
+	return o.with(url.Values{})
+}
+
+// Params renders o as query options.
+func (o ListOptions) Params() map[string]string {
+	return params(o.Values())
+}
+
+// Validate checks o holds values the API accepts.
+func (o ListOptions) Validate() error {
+	if o.Page < 0 || o.PerPage < 0 {
+		return fmt.Errorf("tenkft: negative page %v or per_page %v", o.Page, o.PerPage)
+	}
+
+	return nil
+}
+
+// with adds the parameters of o to v, RawParams last so that they override the others.
+func (o ListOptions) with(v url.Values) url.Values {
+	if len(o.Fields) > 0 {
+		v.Set("fields", strings.Join(o.Fields, ","))
+	}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	for k, val := range o.RawParams {
+		v.Set(k, val)
+	}
+
+	return v
+}
+
+// ProjectListOptions are the query parameters of project lists.
+type ProjectListOptions struct {
+	ListOptions
+	// From and To bound the embedded assignments and the phases listed, ignored when
+	// zero.
+	From, To time.Time
+	// WithArchived includes archived projects.
+	WithArchived bool
+	// WithPhases lists phases along with the projects.
+	WithPhases bool
+	// SortField and SortOrder, "asc" or "desc", order the projects, e.g. "created".
+	SortField string
+	SortOrder string
+}
+
+// Values renders o as URL query values.
+func (o ProjectListOptions) Values() url.Values {
+	v := url.Values{}
+	setWindow(v, o.From, o.To)
+	setBool(v, "with_archived", o.WithArchived)
+	setBool(v, "with_phases", o.WithPhases)
+	if o.SortField != "" {
+		v.Set("sort_field", o.SortField)
+	}
+	if o.SortOrder != "" {
+		v.Set("sort_order", o.SortOrder)
+	}
+
+	return o.ListOptions.with(v)
+}
+
+// Params renders o as query options.
+func (o ProjectListOptions) Params() map[string]string {
+	return params(o.Values())
+}
+
+// Validate checks o holds values the API accepts.
+func (o ProjectListOptions) Validate() error {
+	if o.SortOrder != "" && o.SortOrder != "asc" && o.SortOrder != "desc" {
+		return fmt.Errorf("tenkft: sort_order %q is neither asc nor desc", o.SortOrder)
+	}
+
+	return validate(o.ListOptions, o.From, o.To)
+}
+
+// UserListOptions are the query parameters of user lists.
+type UserListOptions struct {
+	ListOptions
+	// From and To bound the embedded assignments and availabilities, ignored when zero.
+	From, To time.Time
+	// WithArchived includes archived users.
+	WithArchived bool
+}
+
+// Values renders o as URL query values.
+func (o UserListOptions) Values() url.Values {
+	v := url.Values{}
+	setWindow(v, o.From, o.To)
+	setBool(v, "with_archived", o.WithArchived)
+
+	return o.ListOptions.with(v)
+}
+
+// Params renders o as query options.
+func (o UserListOptions) Params() map[string]string {
+	return params(o.Values())
+}
+
+// Validate checks o holds values the API accepts.
+func (o UserListOptions) Validate() error {
+	return validate(o.ListOptions, o.From, o.To)
+}
+
+// AssignmentListOptions are the query parameters of user and project assignment lists.
+type AssignmentListOptions struct {
+	ListOptions
+	// From and To only list the assignments overlapping the window, ignored when zero.
+	From, To time.Time
+	// WithPhases includes the assignments to the phases of a project.
+	WithPhases bool
+}
+
+// Values renders o as URL query values.
+func (o AssignmentListOptions) Values() url.Values {
+	v := url.Values{}
+	setWindow(v, o.From, o.To)
+	setBool(v, "with_phases", o.WithPhases)
+
+	return o.ListOptions.with(v)
+}
+
+// Params renders o as query options.
+func (o AssignmentListOptions) Params() map[string]string {
+	return params(o.Values())
+}
+
+// Validate checks o holds values the API accepts.
+func (o AssignmentListOptions) Validate() error {
+	return validate(o.ListOptions, o.From, o.To)
+}
+
+// TimeEntryListOptions are the query parameters of time entry lists.
+type TimeEntryListOptions struct {
+	ListOptions
+	// From and To only list the entries dated within the window, ignored when zero.
+	From, To time.Time
+	// WithSuggestions includes the entries suggested from the schedule, see
+	// TimeEntry.IsSuggestion.
+	WithSuggestions bool
+}
+
+// Values renders o as URL query values.
+func (o TimeEntryListOptions) Values() url.Values {
+	v := url.Values{}
+	setWindow(v, o.From, o.To)
+	setBool(v, "with_suggestions", o.WithSuggestions)
+
+	return o.ListOptions.with(v)
+}
+
+// Params renders o as query options.
+func (o TimeEntryListOptions) Params() map[string]string {
+	return params(o.Values())
+}
+
+// Validate checks o holds values the API accepts.
+func (o TimeEntryListOptions) Validate() error {
+	return validate(o.ListOptions, o.From, o.To)
+}
+
+func setWindow(v url.Values, from, to time.Time) {
+	if !from.IsZero() {
+		v.Set("from", from.Format(DateFormat))
+	}
+	if !to.IsZero() {
+		v.Set("to", to.Format(DateFormat))
+	}
+}
+
+func setBool(v url.Values, key string, b bool) {
+	if b {
+		v.Set(key, "true")
+	}
+}
+
+// validate checks the shared options and that the window doesn't end before it starts.
+func validate(o ListOptions, from, to time.Time) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("tenkft: to %v is before from %v", to.Format(DateFormat), from.Format(DateFormat))
+	}
+
+	return nil
+}
+
+// params flattens v into query options, keeping the first value of every key.
+func params(v url.Values) map[string]string {
+	opts := map[string]string{}
+	for k, vals := range v {
+		opts[k] = vals[0]
+	}
+
+	return opts
+}
@@ -0,0 +1,51 @@
+package tenkft
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListOptions(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	opts := ProjectListOptions{
+		ListOptions: ListOptions{
+			Fields:    []string{"tags", "assignments"},
+			PerPage:   100,
+			RawParams: map[string]string{"with_phases": "false", "project_code": "A&B"},
+		},
+		From:         from,
+		WithArchived: true,
+		WithPhases:   true,
+	}
+
+	expected := map[string]string{
+		"fields":        "tags,assignments",
+		"per_page":      "100",
+		"from":          "2024-03-01",
+		"with_archived": "true",
+		"with_phases":   "false",
+		"project_code":  "A&B",
+	}
+	if params := opts.Params(); !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+
+	if q := opts.Values().Encode(); q != "fields=tags%2Cassignments&from=2024-03-01&per_page=100&project_code=A%26B&with_archived=true&with_phases=false" {
+		t.Errorf("unexpected query %v", q)
+	}
+
+	if err := opts.Validate(); err != nil {
+		t.Errorf("expected valid options, got %v", err)
+	}
+
+	for name, o := range map[string]interface{ Validate() error }{
+		"window":   UserListOptions{From: from, To: from.AddDate(0, 0, -1)},
+		"per page": AssignmentListOptions{ListOptions: ListOptions{PerPage: -1}},
+		"sort":     ProjectListOptions{SortOrder: "up"},
+	} {
+		if o.Validate() == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Ping is the outcome of Client.Ping.
+type Ping struct {
+	// Latency is the time the request took, including reading the headers.
+	Latency time.Duration
+	// RateLimit and RateLimitRemaining are the request budget reported by the
+	// X-RateLimit-Limit and X-RateLimit-Remaining headers, -1 when absent.
+	RateLimit          int
+	RateLimitRemaining int
+}
+
+// Ping performs the smallest authenticated request available, without retries, and
+// reports how long it took and the rate limit headroom left. Use it for readiness
+// probes, err is set when 10000ft is unreachable or rejects the token.
+func (c *Client) Ping(ctx context.Context) (ping Ping, resp *http.Response, err error) {
+	start := time.Now()
+	resp, err = c.check(ctx)
+	ping.Latency = time.Since(start)
+
+	ping.RateLimit, ping.RateLimitRemaining = -1, -1
+	if resp != nil {
+		ping.RateLimit = headerInt(resp, "X-RateLimit-Limit")
+		ping.RateLimitRemaining = headerInt(resp, "X-RateLimit-Remaining")
+	}
+
+	return
+}
+
+// headerInt returns the integer value of a response header, -1 if it is absent or
+// malformed.
+func headerInt(resp *http.Response, key string) int {
+	n, err := strconv.Atoi(resp.Header.Get(key))
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
@@ -0,0 +1,35 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/roles" || r.Header.Get("auth") != "test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		fmt.Fprint(w, `{"data": [], "paging": {}}`)
+	}))
+	defer srv.Close()
+
+	ping, _, err := (&Client{token: "test", env: srv.URL}).Ping(context.Background())
+	if err != nil {
+		t.Fatal("could not ping", err)
+	}
+	if ping.RateLimit != 100 || ping.RateLimitRemaining != 42 || ping.Latency <= 0 {
+		t.Errorf("expected the rate limit headroom and latency, got %+v", ping)
+	}
+
+	_, _, err = (&Client{token: "bad", env: srv.URL}).Ping(context.Background())
+	if err == nil {
+		t.Error("expected a rejected token to fail the ping")
+	}
+}
@@ -0,0 +1,128 @@
+package tenkft
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"unicode"
+)
+
+// ErrDuplicateProjectCode is returned by ProjectCodes.Create when a project already
+// uses the code.
+var ErrDuplicateProjectCode = errors.New("tenkft: project code already in use")
+
+// ProjectCodes generates project codes from a pattern and guards their uniqueness
+// against the account's projects, which are loaded on first use.
+type ProjectCodes struct {
+	// Pattern is a fmt format given the prefix and a sequence number, e.g. "%s-%04d".
+	Pattern string
+	// Prefix returns the prefix of a project's code, by default the first three
+	// letters of its client, upper cased, or "PRJ" without client.
+	Prefix func(p *Project) string
+
+	c      *Client
+	mu     sync.Mutex
+	used   map[string]bool
+	next   map[string]int
+	loaded bool
+}
+
+// Next returns the first unused code for p, without reserving it.
+func (pc *ProjectCodes) Next(p *Project) (string, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if err := pc.load(); err != nil {
+		return "", err
+	}
+
+	code, _ := pc.nextCode(p)
+
+	return code, nil
+}
+
+// Create creates p with a generated code, or with its own ProjectCode when set as
+// long as no other project uses it. A generated code is only taken once p is created,
+// p.ProjectCode being left empty on error.
+func (pc *ProjectCodes) Create(p *Project) (*http.Response, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if err := pc.load(); err != nil {
+		return nil, err
+	}
+
+	generated, seq := p.ProjectCode == "", 0
+	if generated {
+		p.ProjectCode, seq = pc.nextCode(p)
+	} else if pc.used[p.ProjectCode] {
+		return nil, fmt.Errorf("%w: %v", ErrDuplicateProjectCode, p.ProjectCode)
+	}
+
+	resp, err := pc.c.CreateProject(p)
+	if err != nil {
+		if generated {
+			p.ProjectCode = ""
+		}
+		return resp, err
+	}
+	pc.used[p.ProjectCode] = true
+	if generated {
+		pc.next[pc.prefix(p)] = seq
+	}
+
+	return resp, nil
+}
+
+func (pc *ProjectCodes) load() error {
+	if pc.loaded {
+		return nil
+	}
+
+	projects, _, err := pc.c.GetAllProjects(map[string]string{"with_archived": "true"})
+	if err != nil {
+		return err
+	}
+
+	pc.used, pc.next = map[string]bool{}, map[string]int{}
+	for _, p := range projects.Data {
+		if p.baseProject != nil && p.ProjectCode != "" {
+			pc.used[p.ProjectCode] = true
+		}
+	}
+	pc.loaded = true
+
+	return nil
+}
+
+// nextCode returns the first unused code for p and its sequence number.
+func (pc *ProjectCodes) nextCode(p *Project) (string, int) {
+	prefix := pc.prefix(p)
+	for seq := pc.next[prefix] + 1; ; seq++ {
+		if code := fmt.Sprintf(pc.Pattern, prefix, seq); !pc.used[code] {
+			return code, seq
+		}
+	}
+}
+
+func (pc *ProjectCodes) prefix(p *Project) string {
+	if pc.Prefix != nil {
+		return pc.Prefix(p)
+	}
+
+	letters := []rune{}
+	for _, r := range p.Client {
+		if unicode.IsLetter(r) {
+			letters = append(letters, unicode.ToUpper(r))
+		}
+		if len(letters) == 3 {
+			break
+		}
+	}
+	if len(letters) == 0 {
+		return "PRJ"
+	}
+
+	return string(letters)
+}
@@ -0,0 +1,56 @@
+package tenkft
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProjectCodes(t *testing.T) {
+	created, fail := 0, false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if fail {
+				http.Error(w, `{"message": "unavailable"}`, http.StatusServiceUnavailable)
+				return
+			}
+			created++
+			fmt.Fprint(w, `{"id": 99}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1, "project_code": "ACM-0001"}, {"id": 2, "project_code": "ACM-0002"}], "paging": {}}`)
+	}))
+	defer srv.Close()
+	codes := NewProjectCodes(&Client{token: "test", env: srv.URL}, "%s-%04d")
+
+	p := NewProject()
+	p.Client = "Acme Inc"
+	if code, _ := codes.Next(p); code != "ACM-0003" {
+		t.Errorf("expected ACM-0003, got %v", code)
+	}
+
+	fail = true
+	if _, err := codes.Create(p); err == nil || p.ProjectCode != "" {
+		t.Errorf("expected a failed creation to leave the code empty, got %v, %q", err, p.ProjectCode)
+	}
+
+	fail = false
+	if _, err := codes.Create(p); err != nil {
+		t.Fatal("could not create the project", err)
+	}
+	if p.ProjectCode != "ACM-0003" {
+		t.Errorf("expected neither Next nor a failed creation to take a code, got %v", p.ProjectCode)
+	}
+
+	if code, _ := codes.Next(p); code != "ACM-0004" {
+		t.Errorf("expected the created code to be taken, got %v", code)
+	}
+
+	dup := NewProject()
+	dup.ProjectCode = "ACM-0002"
+	if _, err := codes.Create(dup); !errors.Is(err, ErrDuplicateProjectCode) || created != 1 {
+		t.Errorf("expected a duplicate code to be refused before creation, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Assignment statuses.
+const (
+	AssignmentPending   = "pending"
+	AssignmentProposed  = "proposed"
+	AssignmentConfirmed = "confirmed"
+	AssignmentRejected  = "rejected"
+)
+
+// ApprovalQueue holds the assignments awaiting a decision keyed by the user who
+// approves them, the owner of their project. Projects without an owner are under 0.
+type ApprovalQueue map[UserID][]*Assignment
+
+// GetApprovalQueue returns the pending and proposed assignments between from and to,
+// grouped by approver.
+func (c *Client) GetApprovalQueue(from, to time.Time) (ApprovalQueue, *http.Response, error) {
+	return c.GetApprovalQueueCtx(context.Background(), from, to)
+}
+
+// GetApprovalQueueCtx is GetApprovalQueue honouring ctx.
+func (c *Client) GetApprovalQueueCtx(ctx context.Context, from, to time.Time) (queue ApprovalQueue, resp *http.Response, err error) {
+	projects, resp, err := c.GetAllProjectsWithAssignmentsCtx(ctx, from, to, map[string]string{})
+	if err != nil {
+		return
+	}
+
+	queue = ApprovalQueue{}
+	for _, p := range projects.Data {
+		for _, a := range p.Assignments.Data {
+			if a.Status == AssignmentPending || a.Status == AssignmentProposed {
+				queue[p.OwnerID] = append(queue[p.OwnerID], a)
+			}
+		}
+	}
+
+	return
+}
+
+// ConfirmAssignments sets the status of assignments to confirmed, stopping at the
+// first failure.
+func (c *Client) ConfirmAssignments(assignments []*Assignment) (*http.Response, error) {
+	return c.ConfirmAssignmentsCtx(context.Background(), assignments)
+}
+
+// ConfirmAssignmentsCtx is ConfirmAssignments honouring ctx.
+func (c *Client) ConfirmAssignmentsCtx(ctx context.Context, assignments []*Assignment) (*http.Response, error) {
+	return c.setAssignmentsStatus(ctx, assignments, AssignmentConfirmed)
+}
+
+// RejectAssignments sets the status of assignments to rejected, stopping at the first
+// failure.
+func (c *Client) RejectAssignments(assignments []*Assignment) (*http.Response, error) {
+	return c.RejectAssignmentsCtx(context.Background(), assignments)
+}
+
+// RejectAssignmentsCtx is RejectAssignments honouring ctx.
+func (c *Client) RejectAssignmentsCtx(ctx context.Context, assignments []*Assignment) (*http.Response, error) {
+	return c.setAssignmentsStatus(ctx, assignments, AssignmentRejected)
+}
+
+func (c *Client) setAssignmentsStatus(ctx context.Context, assignments []*Assignment, status string) (resp *http.Response, err error) {
+	body := map[string]string{"status": status}
+	for _, a := range assignments {
+		resp, err = userAssignmentsEndpoint.Update(ctx, c, int(a.ID), body, a, int(a.UserID))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
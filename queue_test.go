@@ -0,0 +1,52 @@
+package tenkft
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApprovalQueue(t *testing.T) {
+	var updates []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			updates = append(updates, r.URL.Path+" "+string(body))
+			fmt.Fprint(w, `{"status": "confirmed"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"data": [
+			{"id": 7, "owner_id": 100, "assignments": {"data": [
+				{"id": 1, "user_id": 1, "status": "pending"},
+				{"id": 2, "user_id": 2, "status": "confirmed"}
+			]}},
+			{"id": 8, "owner_id": 200, "assignments": {"data": [{"id": 3, "user_id": 1, "status": "proposed"}]}}
+		], "paging": {}}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	d := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	queue, _, err := client.GetApprovalQueue(d, d.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal("could not get the approval queue", err)
+	}
+
+	if len(queue) != 2 || len(queue[100]) != 1 || queue[100][0].ID != 1 || queue[200][0].ID != 3 {
+		t.Errorf("expected assignments 1 and 3 queued for owners 100 and 200, got %+v", queue)
+	}
+
+	if _, err := client.ConfirmAssignments(queue[100]); err != nil {
+		t.Fatal("could not confirm", err)
+	}
+	if fmt.Sprint(updates) != `[/users/1/assignments/1 {"status":"confirmed"}]` {
+		t.Errorf("expected a status update of assignment 1, got %v", updates)
+	}
+	if queue[100][0].Status != AssignmentConfirmed {
+		t.Errorf("expected the assignment to be updated, got %v", queue[100][0].Status)
+	}
+}
@@ -0,0 +1,135 @@
+package tenkft
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// CompensationFields are the JSON fields holding bill rates and dollar figures, pass
+// them to WithRedaction for services that must never see compensation data.
+var CompensationFields = []string{
+	"bill_rate",
+	"billrate",
+	"rate",
+	"confirmed_dollars",
+	"approved_dollars",
+	"unconfirmed_dollars",
+	"scheduled_dollars",
+	"future_dollars",
+}
+
+// WithRedaction drops the given JSON fields, at any depth, from every response before
+// it is decoded, so they never reach the returned structs - including untyped fields
+// and the *Into variants. Redacted fields decode as zero values.
+func WithRedaction(fields ...string) ClientOption {
+	return func(c *Client) {
+		if c.redacted == nil {
+			c.redacted = map[string]bool{}
+		}
+		for _, f := range fields {
+			c.redacted[f] = true
+		}
+	}
+}
+
+// decode decodes the JSON in r into out, dropping redacted fields first when the
+// client has any, and keeping numbers as json.Number with WithUseNumber.
+func (c *Client) decode(r io.Reader, out interface{}) error {
+	if len(c.redacted) == 0 && !c.useNumber {
+		return json.NewDecoder(r).Decode(out)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+	redact(tree, c.redacted)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(tree); err != nil {
+		return err
+	}
+
+	dec = json.NewDecoder(&buf)
+	if c.useNumber {
+		dec.UseNumber()
+	}
+
+	if err := dec.Decode(out); err != nil {
+		return err
+	}
+
+	if c.useNumber {
+		useNumbers(reflect.ValueOf(out), tree)
+	}
+
+	return nil
+}
+
+// redact deletes fields from every object in a decoded JSON tree.
+func redact(v interface{}, fields map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if fields[k] {
+				delete(v, k)
+				continue
+			}
+			redact(child, fields)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redact(child, fields)
+		}
+	}
+}
+
+// useNumbers copies the values of tree, decoded with UseNumber, into the untyped
+// fields of v. Types with an UnmarshalJSON method decode through json.Unmarshal, which
+// doesn't inherit UseNumber from the outer decoder.
+func useNumbers(v reflect.Value, tree interface{}) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			useNumbers(v.Elem(), tree)
+		}
+	case reflect.Interface:
+		if v.CanSet() && !v.IsNil() && v.Type().NumMethod() == 0 {
+			v.Set(reflect.ValueOf(tree))
+		}
+	case reflect.Slice:
+		items, ok := tree.([]interface{})
+		if !ok {
+			return
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			useNumbers(v.Index(i), items[i])
+		}
+	case reflect.Struct:
+		object, ok := tree.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if sf.Anonymous && name == "" {
+				useNumbers(v.Field(i), object)
+				continue
+			}
+
+			if name == "" {
+				name = sf.Name
+			}
+			if child, ok := object[name]; ok && name != "-" {
+				useNumbers(v.Field(i), child)
+			}
+		}
+	}
+}
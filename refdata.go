@@ -0,0 +1,233 @@
+package tenkft
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RefData lazily loads and caches account reference data - roles, disciplines, leave
+// types and tags - so that write paths can look values up by name without fetching
+// them every time. Each kind is loaded on first use and again once TTL has passed.
+// The returned maps are shared and must not be modified.
+type RefData struct {
+	TTL time.Duration
+
+	c *Client
+
+	roles       refKind[*Role]
+	disciplines refKind[*Discipline]
+	leaveTypes  refKind[*LeaveType]
+	tags        refKind[*Tag]
+}
+
+// refKind is a kind of reference data keyed by name. Each kind has a lock of its own,
+// so that a slow load of one, e.g. tags, doesn't hold up lookups of the others while
+// concurrent lookups of the same kind share its load.
+type refKind[T any] struct {
+	mu       sync.Mutex
+	byName   map[string]T
+	loadedAt time.Time
+}
+
+// get returns the kind, calling load first unless it was loaded less than ttl ago.
+func (k *refKind[T]) get(ttl time.Duration, load func() (map[string]T, error)) (map[string]T, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.loadedAt.IsZero() && time.Since(k.loadedAt) < ttl {
+		return k.byName, nil
+	}
+
+	byName, err := load()
+	if err != nil {
+		return nil, err
+	}
+	k.byName, k.loadedAt = byName, time.Now()
+
+	return k.byName, nil
+}
+
+// expire makes the next get load the kind again.
+func (k *refKind[T]) expire() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.loadedAt = time.Time{}
+}
+
+// Refresh discards everything loaded so far, the next lookup of each kind refetches it.
+func (r *RefData) Refresh() {
+	r.roles.expire()
+	r.disciplines.expire()
+	r.leaveTypes.expire()
+	r.tags.expire()
+}
+
+// Roles returns the account roles keyed by value.
+func (r *RefData) Roles() (map[string]*Role, error) {
+	return r.RolesCtx(context.Background())
+}
+
+// RolesCtx is Roles honouring ctx.
+func (r *RefData) RolesCtx(ctx context.Context) (map[string]*Role, error) {
+	return r.roles.get(r.TTL, func() (map[string]*Role, error) {
+		roles, _, err := r.c.GetAllRolesCtx(ctx, map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+
+		byName := make(map[string]*Role, len(roles.Data))
+		for _, role := range roles.Data {
+			byName[role.Value] = role
+		}
+
+		return byName, nil
+	})
+}
+
+// RoleByName returns the role with the given value, or nil if there is none.
+func (r *RefData) RoleByName(name string) (*Role, error) {
+	return r.RoleByNameCtx(context.Background(), name)
+}
+
+// RoleByNameCtx is RoleByName honouring ctx.
+func (r *RefData) RoleByNameCtx(ctx context.Context, name string) (*Role, error) {
+	roles, err := r.RolesCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles[name], nil
+}
+
+// Disciplines returns the account disciplines keyed by value.
+func (r *RefData) Disciplines() (map[string]*Discipline, error) {
+	return r.DisciplinesCtx(context.Background())
+}
+
+// DisciplinesCtx is Disciplines honouring ctx.
+func (r *RefData) DisciplinesCtx(ctx context.Context) (map[string]*Discipline, error) {
+	return r.disciplines.get(r.TTL, func() (map[string]*Discipline, error) {
+		disciplines, _, err := r.c.GetAllDisciplinesCtx(ctx, map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+
+		byName := make(map[string]*Discipline, len(disciplines.Data))
+		for _, d := range disciplines.Data {
+			byName[d.Value] = d
+		}
+
+		return byName, nil
+	})
+}
+
+// DisciplineByName returns the discipline with the given value, or nil if there is none.
+func (r *RefData) DisciplineByName(name string) (*Discipline, error) {
+	return r.DisciplineByNameCtx(context.Background(), name)
+}
+
+// DisciplineByNameCtx is DisciplineByName honouring ctx.
+func (r *RefData) DisciplineByNameCtx(ctx context.Context, name string) (*Discipline, error) {
+	disciplines, err := r.DisciplinesCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return disciplines[name], nil
+}
+
+// LeaveTypes returns the account leave types keyed by name.
+func (r *RefData) LeaveTypes() (map[string]*LeaveType, error) {
+	return r.LeaveTypesCtx(context.Background())
+}
+
+// LeaveTypesCtx is LeaveTypes honouring ctx.
+func (r *RefData) LeaveTypesCtx(ctx context.Context) (map[string]*LeaveType, error) {
+	return r.leaveTypes.get(r.TTL, func() (map[string]*LeaveType, error) {
+		leaveTypes, _, err := r.c.GetAllLeaveTypesCtx(ctx, map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+
+		byName := make(map[string]*LeaveType, len(leaveTypes.Data))
+		for _, lt := range leaveTypes.Data {
+			byName[lt.Name] = lt
+		}
+
+		return byName, nil
+	})
+}
+
+// LeaveTypeByName returns the leave type with the given name, or nil if there is none.
+func (r *RefData) LeaveTypeByName(name string) (*LeaveType, error) {
+	return r.LeaveTypeByNameCtx(context.Background(), name)
+}
+
+// LeaveTypeByNameCtx is LeaveTypeByName honouring ctx.
+func (r *RefData) LeaveTypeByNameCtx(ctx context.Context, name string) (*LeaveType, error) {
+	leaveTypes, err := r.LeaveTypesCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaveTypes[name], nil
+}
+
+// Tags returns the tags in use on the account keyed by value. The API has no account
+// wide tag listing, so they are gathered from every user and project - this is by far
+// the most expensive kind to load.
+func (r *RefData) Tags() (map[string]*Tag, error) {
+	return r.TagsCtx(context.Background())
+}
+
+// TagsCtx is Tags honouring ctx.
+func (r *RefData) TagsCtx(ctx context.Context) (map[string]*Tag, error) {
+	return r.tags.get(r.TTL, func() (map[string]*Tag, error) {
+		users, _, err := r.c.GetAllUsersCtx(ctx, map[string]string{"fields": "tags"})
+		if err != nil {
+			return nil, err
+		}
+
+		projects, _, err := r.c.GetAllProjectsCtx(ctx, map[string]string{"fields": "tags"})
+		if err != nil {
+			return nil, err
+		}
+
+		tags := []*Tag{}
+		for _, u := range users.Data {
+			tags = append(tags, u.Tags.Data...)
+		}
+		for _, p := range projects.Data {
+			tags = append(tags, p.Tags.Data...)
+		}
+
+		byName := map[string]*Tag{}
+		for _, t := range tags {
+			if t.baseTag == nil {
+				continue
+			}
+			if _, ok := byName[t.Value]; !ok {
+				byName[t.Value] = t
+			}
+		}
+
+		return byName, nil
+	})
+}
+
+// TagByName returns a tag with the given value, or nil if no user or project carries it.
+func (r *RefData) TagByName(name string) (*Tag, error) {
+	return r.TagByNameCtx(context.Background(), name)
+}
+
+// TagByNameCtx is TagByName honouring ctx.
+func (r *RefData) TagByNameCtx(ctx context.Context, name string) (*Tag, error) {
+	tags, err := r.TagsCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags[name], nil
+}
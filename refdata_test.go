@@ -0,0 +1,131 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefData(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+		fail     bool
+	)
+	routes := map[string]string{
+		"/roles":       `{"data": [{"id": 1, "value": "Designer"}, {"id": 2, "value": "Engineer"}], "paging": {}}`,
+		"/disciplines": `{"data": [{"id": 3, "value": "Design"}], "paging": {}}`,
+		"/leave_types": `{"data": [{"id": 4, "name": "Vacation"}], "paging": {}}`,
+		"/users":       `{"data": [{"id": 5, "tags": {"data": [{"id": 6, "value": "remote"}]}}], "paging": {}}`,
+		"/projects":    `{"data": [{"id": 7, "tags": {"data": [{"id": 8, "value": "remote"}, {"id": 9, "value": "fixed"}]}}], "paging": {}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		failing := fail
+		mu.Unlock()
+
+		if failing {
+			http.Error(w, `{"message": "unavailable"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, routes[r.URL.Path])
+	}))
+	defer srv.Close()
+	ref := NewRefData(&Client{token: "test", env: srv.URL}, time.Hour)
+
+	// Every kind is loaded on first use, then served from the cache.
+	for i := 0; i < 2; i++ {
+		if role, err := ref.RoleByName("Engineer"); err != nil || role == nil || role.ID != 2 {
+			t.Errorf("expected the engineer role, got %+v, %v", role, err)
+		}
+		if d, err := ref.DisciplineByName("Design"); err != nil || d == nil || d.ID != 3 {
+			t.Errorf("expected the design discipline, got %+v, %v", d, err)
+		}
+		if lt, err := ref.LeaveTypeByNameCtx(context.Background(), "Vacation"); err != nil || lt == nil || lt.ID != 4 {
+			t.Errorf("expected the vacation leave type, got %+v, %v", lt, err)
+		}
+		if tag, err := ref.TagByName("remote"); err != nil || tag == nil || tag.ID != 6 {
+			t.Errorf("expected the first remote tag, got %+v, %v", tag, err)
+		}
+		if tag, err := ref.TagByName("fixed"); err != nil || tag == nil || tag.ID != 9 {
+			t.Errorf("expected the fixed project tag, got %+v, %v", tag, err)
+		}
+	}
+	if role, err := ref.RoleByName("Accountant"); err != nil || role != nil {
+		t.Errorf("expected no accountant role, got %+v, %v", role, err)
+	}
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("expected %v to be fetched once, got %v", path, n)
+		}
+	}
+
+	// Expired kinds are reloaded, the others are still cached.
+	ref.roles.loadedAt = time.Now().Add(-2 * time.Hour)
+	ref.Roles()
+	ref.Disciplines()
+	if requests["/roles"] != 2 || requests["/disciplines"] != 1 {
+		t.Errorf("expected only the expired roles to be fetched again, got %v", requests)
+	}
+
+	// Refresh reloads everything, failures aren't cached.
+	ref.Refresh()
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	if _, err := ref.LeaveTypes(); err == nil {
+		t.Error("expected the failing load to be reported")
+	}
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	if lt, err := ref.LeaveTypeByName("Vacation"); err != nil || lt == nil {
+		t.Errorf("expected the leave types to be loaded again, got %+v, %v", lt, err)
+	}
+	if requests["/leave_types"] != 3 {
+		t.Errorf("expected the leave types to be fetched again after the failure, got %v", requests["/leave_types"])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ref.TagsCtx(ctx); err == nil {
+		t.Error("expected a canceled context to fail the load")
+	}
+}
+
+func TestRefDataKindsLoadIndependently(t *testing.T) {
+	loading, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users" {
+			close(loading)
+			<-release
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1, "value": "Designer"}], "paging": {}}`)
+	}))
+	defer srv.Close()
+	defer close(release)
+	ref := NewRefData(&Client{token: "test", env: srv.URL}, time.Hour)
+
+	go ref.Tags()
+	<-loading
+
+	// The roles are served while the tags are still loading.
+	done := make(chan error, 1)
+	go func() {
+		_, err := ref.Roles()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error("could not load roles", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected the roles not to wait for the tags")
+	}
+}
@@ -0,0 +1,86 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Period is the size of the buckets of BucketHours.
+type Period int
+
+// Periods supported by BucketHours.
+const (
+	ByWeek Period = iota
+	ByMonth
+)
+
+// Buckets holds scheduled hours per bucket, keyed by the first day of the bucket
+// formatted as an API date, e.g. "2018-03-05".
+type Buckets map[string]float64
+
+// BucketedHours is the result of BucketHours.
+type BucketedHours struct {
+	// Users holds the hours of every user, keyed by user ID.
+	Users map[tenkft.UserID]Buckets
+	// Projects holds the hours on every project or phase, keyed by assignable ID.
+	Projects map[tenkft.AssignableID]Buckets
+}
+
+// BucketHours spreads the hours assignments are scheduled for into weeks or months,
+// per user and per project. Weeks follow the week start of c, hours are computed as in
+// ScheduledHours.
+func BucketHours(c *tenkft.Client, assignments *tenkft.Assignments, period Period, cal *tenkft.Calendar) (*BucketedHours, error) {
+	result := &BucketedHours{Users: map[tenkft.UserID]Buckets{}, Projects: map[tenkft.AssignableID]Buckets{}}
+	for _, a := range assignments.Data {
+		startsAt, err := c.ParseDate(a.StartsAt)
+		if err != nil {
+			return nil, err
+		}
+		endsAt, err := c.ParseDate(a.EndsAt)
+		if err != nil {
+			return nil, err
+		}
+
+		for bucket := bucketStart(c, startsAt, period); !bucket.After(endsAt); bucket = nextBucket(bucket, period) {
+			hours, err := ScheduledHours(c, cal, a, bucket, nextBucket(bucket, period).AddDate(0, 0, -1))
+			if err != nil {
+				return nil, err
+			}
+			if hours == 0 {
+				continue
+			}
+
+			key := c.FormatDate(bucket)
+			add(result.Users, a.UserID, key, hours)
+			add(result.Projects, a.AssignableID, key, hours)
+		}
+	}
+
+	return result, nil
+}
+
+func bucketStart(c *tenkft.Client, t time.Time, period Period) time.Time {
+	if period == ByMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+
+	from, _ := c.Week(t)
+
+	return from
+}
+
+func nextBucket(bucket time.Time, period Period) time.Time {
+	if period == ByMonth {
+		return bucket.AddDate(0, 1, 0)
+	}
+
+	return bucket.AddDate(0, 0, 7)
+}
+
+func add[K comparable](buckets map[K]Buckets, id K, key string, hours float64) {
+	if buckets[id] == nil {
+		buckets[id] = Buckets{}
+	}
+	buckets[id][key] += hours
+}
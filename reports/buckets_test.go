@@ -0,0 +1,36 @@
+package reports
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestBucketHours(t *testing.T) {
+	c, _ := tenkft.NewClient("test", tenkft.Production, tenkft.WithLocation(time.UTC))
+	cal := tenkft.NewCalendar(time.UTC, nil)
+
+	// Thursday 29 March to Tuesday 3 April 2018, 4 working days.
+	var assignments tenkft.Assignments
+	json.Unmarshal([]byte(`{"data": [
+		{"assignable_id": 7, "user_id": 1, "allocation_mode": "hours_per_day", "hours_per_day": 2, "starts_at": "2018-03-29", "ends_at": "2018-04-03"}
+	]}`), &assignments)
+
+	weeks, err := BucketHours(c, &assignments, ByWeek, cal)
+	if err != nil {
+		t.Fatal("could not bucket hours", err)
+	}
+	if w := weeks.Users[1]; len(w) != 2 || w["2018-03-26"] != 4 || w["2018-04-02"] != 4 {
+		t.Errorf("expected 4 hours in each week, got %v", w)
+	}
+
+	months, err := BucketHours(c, &assignments, ByMonth, cal)
+	if err != nil {
+		t.Fatal("could not bucket hours", err)
+	}
+	if m := months.Projects[7]; len(m) != 2 || m["2018-03-01"] != 4 || m["2018-04-01"] != 4 {
+		t.Errorf("expected 4 hours in each month, got %v", m)
+	}
+}
@@ -0,0 +1,108 @@
+package reports
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/workco/go-tenkft"
+)
+
+// Partition names the groups a user belongs to, none leaves the user out. Write your
+// own when the org structure doesn't map onto a single 10000ft field.
+type Partition func(u *tenkft.User) []string
+
+// ByEmailDomain groups users by the domain of their email, lower cased.
+func ByEmailDomain(u *tenkft.User) []string {
+	_, domain, ok := strings.Cut(u.Email, "@")
+	if !ok || domain == "" {
+		return nil
+	}
+
+	return []string{strings.ToLower(domain)}
+}
+
+// ByLocation groups users by location.
+func ByLocation(u *tenkft.User) []string {
+	if u.Location == "" {
+		return nil
+	}
+
+	return []string{u.Location}
+}
+
+// ByTag groups users by tag, users with several tags belong to several groups. Users
+// need their tags fetched, see tenkft.NestedTags.
+func ByTag(u *tenkft.User) []string {
+	names := []string{}
+	for _, t := range u.Tags.Data {
+		if t.Value != "" {
+			names = append(names, t.Value)
+		}
+	}
+
+	return names
+}
+
+// Group is a named set of users, use it to scope the data a report runs on.
+type Group struct {
+	Name  string
+	Users []*tenkft.User
+
+	ids map[tenkft.UserID]bool
+}
+
+// GroupUsers partitions users into groups sorted by name.
+func GroupUsers(users *tenkft.Users, partition Partition) []*Group {
+	byName := map[string]*Group{}
+	for _, u := range users.Data {
+		for _, name := range partition(u) {
+			g, ok := byName[name]
+			if !ok {
+				g = &Group{Name: name, ids: map[tenkft.UserID]bool{}}
+				byName[name] = g
+			}
+			if !g.ids[u.ID] {
+				g.ids[u.ID] = true
+				g.Users = append(g.Users, u)
+			}
+		}
+	}
+
+	groups := make([]*Group, 0, len(byName))
+	for _, g := range byName {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	return groups
+}
+
+// Contains reports whether the user with the given ID belongs to g.
+func (g *Group) Contains(userID tenkft.UserID) bool {
+	return g.ids[userID]
+}
+
+// Assignments returns the assignments of the members of g, e.g. to compute the
+// ProjectedMargins of a group.
+func (g *Group) Assignments(assignments *tenkft.Assignments) *tenkft.Assignments {
+	scoped := tenkft.NewAssignments()
+	for _, a := range assignments.Data {
+		if g.Contains(a.UserID) {
+			scoped.Data = append(scoped.Data, a)
+		}
+	}
+
+	return scoped
+}
+
+// TimeEntries returns the time entries of the members of g.
+func (g *Group) TimeEntries(timeEntries *tenkft.TimeEntries) *tenkft.TimeEntries {
+	scoped := tenkft.NewTimeEntries()
+	for _, te := range timeEntries.Data {
+		if g.Contains(te.UserID) {
+			scoped.Data = append(scoped.Data, te)
+		}
+	}
+
+	return scoped
+}
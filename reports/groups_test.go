@@ -0,0 +1,36 @@
+package reports
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestGroupUsers(t *testing.T) {
+	var users tenkft.Users
+	err := json.Unmarshal([]byte(`{"data": [
+		{"id": 1, "email": "ada@Work.co", "tags": {"data": [{"value": "design"}, {"value": "lead"}]}},
+		{"id": 2, "email": "bob@contractor.com", "tags": {"data": [{"value": "design"}]}},
+		{"id": 3, "email": "eve@work.co"}
+	]}`), &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := GroupUsers(&users, ByEmailDomain)
+	if len(groups) != 2 || groups[1].Name != "work.co" || len(groups[1].Users) != 2 {
+		t.Errorf("expected contractor.com and work.co with 2 users, got %+v", groups)
+	}
+
+	groups = GroupUsers(&users, ByTag)
+	if len(groups) != 2 || groups[0].Name != "design" || len(groups[0].Users) != 2 || !groups[1].Contains(1) {
+		t.Errorf("expected design with 2 users and lead with ada, got %+v", groups)
+	}
+
+	var assignments tenkft.Assignments
+	json.Unmarshal([]byte(`{"data": [{"id": 10, "user_id": 1}, {"id": 30, "user_id": 3}]}`), &assignments)
+	if scoped := groups[0].Assignments(&assignments); len(scoped.Data) != 1 || scoped.Data[0].ID != 10 {
+		t.Errorf("expected only ada's assignment in the design group, got %+v", scoped.Data)
+	}
+}
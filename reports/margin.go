@@ -0,0 +1,137 @@
+// Package reports computes figures 10000ft doesn't report itself out of the data
+// fetched with a tenkft.Client.
+package reports
+
+import (
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+// CostRates is the hourly cost of people, supplied by the caller since 10000ft doesn't
+// know what people cost. A user's rate is looked up by user ID, then by role, falling
+// back to Default.
+type CostRates struct {
+	ByUser  map[tenkft.UserID]tenkft.Money
+	ByRole  map[string]tenkft.Money
+	Default tenkft.Money
+}
+
+// Rate returns the hourly cost of u, nil users get Default.
+func (r *CostRates) Rate(u *tenkft.User) tenkft.Money {
+	if u == nil {
+		return r.Default
+	}
+
+	if rate, ok := r.ByUser[u.ID]; ok {
+		return rate
+	}
+
+	if rate, ok := r.ByRole[u.Role]; ok {
+		return rate
+	}
+
+	return r.Default
+}
+
+// Margin is the projected margin of a project or phase over a period.
+type Margin struct {
+	AssignableID tenkft.AssignableID
+	// Hours is the time scheduled on the assignable.
+	Hours   float64
+	Revenue tenkft.Money
+	Cost    tenkft.Money
+}
+
+// Profit returns Revenue minus Cost.
+func (m *Margin) Profit() (tenkft.Money, error) {
+	return m.Revenue.Sub(m.Cost)
+}
+
+// Percent returns the profit as a share of the revenue, 0 without revenue.
+func (m *Margin) Percent() float64 {
+	if m.Revenue.Minor == 0 {
+		return 0
+	}
+
+	return float64(m.Revenue.Minor-m.Cost.Minor) / float64(m.Revenue.Minor)
+}
+
+// ProjectedMargins returns the margin of every project and phase assignments are
+// scheduled on between from and to, keyed by assignable ID. Revenue is the scheduled
+// hours at the bill rate of each assignment, cost the same hours at the cost rate of
+// the assigned user. Hours follow cal, and the user's availabilities when assignments
+// were expanded, see tenkft.WithAssignmentExpansion. Leave assignments must be left out.
+func ProjectedMargins(c *tenkft.Client, cal *tenkft.Calendar, rates *CostRates, assignments *tenkft.Assignments, from, to time.Time) (map[tenkft.AssignableID]*Margin, error) {
+	margins := map[tenkft.AssignableID]*Margin{}
+	for _, a := range assignments.Data {
+		hours, err := ScheduledHours(c, cal, a, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := margins[a.AssignableID]
+		if !ok {
+			m = &Margin{AssignableID: a.AssignableID, Revenue: c.Money(0), Cost: c.Money(0)}
+			margins[a.AssignableID] = m
+		}
+
+		m.Hours += hours
+		if m.Revenue, err = m.Revenue.Add(c.Money(a.BillRate).Mul(hours)); err != nil {
+			return nil, err
+		}
+		if m.Cost, err = m.Cost.Add(rates.Rate(a.User).Mul(hours)); err != nil {
+			return nil, err
+		}
+	}
+
+	return margins, nil
+}
+
+// ScheduledHours returns the hours a is scheduled for between from and to, per its
+// allocation mode: hours per day, a percentage of the user's working hours, or fixed
+// hours spread evenly over its working days.
+func ScheduledHours(c *tenkft.Client, cal *tenkft.Calendar, a *tenkft.Assignment, from, to time.Time) (float64, error) {
+	startsAt, err := c.ParseDate(a.StartsAt)
+	if err != nil {
+		return 0, err
+	}
+	endsAt, err := c.ParseDate(a.EndsAt)
+	if err != nil {
+		return 0, err
+	}
+
+	first, last := latest(startsAt, from), earliest(endsAt, to)
+	if first.After(last) {
+		return 0, nil
+	}
+
+	switch a.AllocationMode {
+	case tenkft.AllocationFixed:
+		days := len(cal.WorkingDaysBetween(a.User, startsAt, endsAt))
+		if days == 0 {
+			return 0, nil
+		}
+		return a.FixedHours * float64(len(cal.WorkingDaysBetween(a.User, first, last))) / float64(days), nil
+	case tenkft.AllocationPercent:
+		return a.Percent * cal.WorkingHoursBetween(a.User, first, last), nil
+	default:
+		return a.HoursPerDay * float64(len(cal.WorkingDaysBetween(a.User, first, last))), nil
+	}
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,40 @@
+package reports
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestProjectedMargins(t *testing.T) {
+	c, _ := tenkft.NewClient("test", tenkft.Production, tenkft.WithLocation(time.UTC))
+	cal := tenkft.NewCalendar(time.UTC, nil)
+
+	// Monday 5 to Friday 9 March 2018.
+	var assignments tenkft.Assignments
+	err := json.Unmarshal([]byte(`{"data": [
+		{"assignable_id": 7, "user_id": 1, "allocation_mode": "percent", "percent": 0.5, "bill_rate": 200, "starts_at": "2018-03-05", "ends_at": "2018-03-09"},
+		{"assignable_id": 7, "user_id": 2, "allocation_mode": "hours_per_day", "hours_per_day": 2, "bill_rate": 100, "starts_at": "2018-03-01", "ends_at": "2018-03-31"},
+		{"assignable_id": 8, "user_id": 1, "allocation_mode": "fixed", "fixed_hours": 10, "bill_rate": 100, "starts_at": "2018-03-05", "ends_at": "2018-03-09"}
+	]}`), &assignments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rates := &CostRates{Default: tenkft.NewMoney(50, "USD")}
+	from := time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC)
+	margins, err := ProjectedMargins(c, cal, rates, &assignments, from, from.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatal("could not compute margins", err)
+	}
+
+	m := margins[7]
+	if m.Hours != 30 || m.Revenue.Float64() != 5000 || m.Cost.Float64() != 1500 || m.Percent() != 0.7 {
+		t.Errorf("expected 30 hours, 5000 revenue and 1500 cost on project 7, got %+v", m)
+	}
+	if m := margins[8]; m.Hours != 10 || m.Revenue.Float64() != 1000 {
+		t.Errorf("expected the fixed hours on project 8, got %+v", m)
+	}
+}
@@ -0,0 +1,97 @@
+package reports
+
+import (
+	"github.com/workco/go-tenkft"
+)
+
+// Scenario overlays proposed changes on live assignments and projects in memory, so
+// that staffing options can be evaluated with the report helpers before anything is
+// written to 10000ft:
+//
+//	s := reports.NewScenario(projects, assignments)
+//	id := s.ProposeProject(pitch)
+//	a := tenkft.NewAssignment()
+//	a.AssignableID, a.UserID = id, 42
+//	...
+//	s.ProposeAssignment(a)
+//	margins, err := reports.ProjectedMargins(c, cal, rates, s.Assignments(), from, to)
+type Scenario struct {
+	projects    *tenkft.Projects
+	assignments *tenkft.Assignments
+
+	proposedProjects    []*tenkft.Project
+	proposedAssignments []*tenkft.Assignment
+	replaced            map[tenkft.AssignmentID]*tenkft.Assignment
+	removed             map[tenkft.AssignmentID]bool
+}
+
+// NewScenario returns a scenario on top of live projects and assignments, either may
+// be nil. They are never modified.
+func NewScenario(projects *tenkft.Projects, assignments *tenkft.Assignments) *Scenario {
+	if projects == nil {
+		projects = tenkft.NewProjects()
+	}
+	if assignments == nil {
+		assignments = tenkft.NewAssignments()
+	}
+
+	return &Scenario{
+		projects:    projects,
+		assignments: assignments,
+		replaced:    map[tenkft.AssignmentID]*tenkft.Assignment{},
+		removed:     map[tenkft.AssignmentID]bool{},
+	}
+}
+
+// ProposeProject adds a project that doesn't exist yet and returns the ID it gets in
+// the scenario, negative so it never clashes with a live one. Use it as AssignableID of
+// proposed assignments.
+func (s *Scenario) ProposeProject(p *tenkft.Project) tenkft.AssignableID {
+	s.proposedProjects = append(s.proposedProjects, p)
+	p.ID = tenkft.ProjectID(-len(s.proposedProjects))
+
+	return tenkft.AssignableID(p.ID)
+}
+
+// ProposeAssignment adds a, or replaces the live assignment with the same ID when a
+// has one.
+func (s *Scenario) ProposeAssignment(a *tenkft.Assignment) {
+	if a.ID != 0 {
+		s.replaced[a.ID] = a
+		delete(s.removed, a.ID)
+		return
+	}
+
+	s.proposedAssignments = append(s.proposedAssignments, a)
+}
+
+// RemoveAssignment drops the live assignment with the given ID from the scenario.
+func (s *Scenario) RemoveAssignment(id tenkft.AssignmentID) {
+	s.removed[id] = true
+	delete(s.replaced, id)
+}
+
+// Projects returns the live projects followed by the proposed ones.
+func (s *Scenario) Projects() *tenkft.Projects {
+	data := append(append([]*tenkft.Project{}, s.projects.Data...), s.proposedProjects...)
+
+	return &tenkft.Projects{Data: data, Paging: &tenkft.Paging{}}
+}
+
+// Assignments returns the live assignments with the proposed changes applied.
+func (s *Scenario) Assignments() *tenkft.Assignments {
+	data := []*tenkft.Assignment{}
+	for _, a := range s.assignments.Data {
+		if s.removed[a.ID] {
+			continue
+		}
+
+		if replacement, ok := s.replaced[a.ID]; ok {
+			a = replacement
+		}
+		data = append(data, a)
+	}
+	data = append(data, s.proposedAssignments...)
+
+	return &tenkft.Assignments{Data: data, Paging: &tenkft.Paging{}}
+}
@@ -0,0 +1,40 @@
+package reports
+
+import (
+	"testing"
+
+	"github.com/workco/go-tenkft"
+)
+
+func TestScenario(t *testing.T) {
+	live := &tenkft.Assignments{}
+	for _, id := range []tenkft.AssignmentID{1, 2, 3} {
+		a := tenkft.NewAssignment()
+		a.ID, a.HoursPerDay = id, 8
+		live.Data = append(live.Data, a)
+	}
+
+	s := NewScenario(nil, live)
+	pitch := tenkft.NewProject()
+	id := s.ProposeProject(pitch)
+	if id >= 0 || len(s.Projects().Data) != 1 {
+		t.Errorf("expected the proposed project to get a negative ID, got %v", id)
+	}
+
+	proposed := tenkft.NewAssignment()
+	proposed.AssignableID = id
+	s.ProposeAssignment(proposed)
+
+	halved := tenkft.NewAssignment()
+	halved.ID, halved.HoursPerDay = 2, 4
+	s.ProposeAssignment(halved)
+	s.RemoveAssignment(3)
+
+	assignments := s.Assignments().Data
+	if len(assignments) != 3 || assignments[1].HoursPerDay != 4 || assignments[2] != proposed {
+		t.Errorf("expected 1, the replaced 2 and the proposed assignment, got %+v", assignments)
+	}
+	if live.Data[1].HoursPerDay != 8 || len(live.Data) != 3 {
+		t.Error("expected the live assignments not to be modified")
+	}
+}
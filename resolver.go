@@ -0,0 +1,316 @@
+package tenkft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrPhaseNotFound is returned by Resolver.AssignableID when a project has no phase of
+// the given name.
+var ErrPhaseNotFound = errors.New("tenkft: phase not found")
+
+// Resolver maps the IDs that assignments and time entries refer to onto the records
+// they point to. The first time an unknown ID of a kind is seen the whole collection of
+// that kind is loaded, so resolving thousands of IDs costs a handful of paginated calls
+// instead of one call per ID.
+type Resolver struct {
+	c  *Client
+	mu sync.Mutex
+
+	users             map[UserID]*User
+	usersLoaded       bool
+	projects          map[AssignableID]*Project
+	leaveTypes        map[AssignableID]*LeaveType
+	assignablesLoaded bool
+	billRates         map[int]float64
+	billRatesLoaded   map[AssignableID]bool
+}
+
+// UserName returns the display name of a user, or "" if no such user exists.
+func (r *Resolver) UserName(id UserID) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.loadUsers([]UserID{id}); err != nil {
+		return "", err
+	}
+
+	u, ok := r.users[id]
+	if !ok {
+		return "", nil
+	}
+
+	return userName(u), nil
+}
+
+// AssignableName returns the name of the project, phase or leave type an assignable ID
+// points to. Phases are returned as "<project> / <phase>". "" is returned for unknown IDs.
+func (r *Resolver) AssignableName(id AssignableID) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.loadAssignables([]AssignableID{id}); err != nil {
+		return "", err
+	}
+
+	if lt, ok := r.leaveTypes[id]; ok {
+		return lt.Name, nil
+	}
+
+	p, ok := r.projects[id]
+	if !ok {
+		return "", nil
+	}
+
+	if parent, ok := r.projects[AssignableID(p.ParentID)]; ok && p.PhaseName != "" {
+		return parent.Name + " / " + p.PhaseName, nil
+	}
+
+	return p.Name, nil
+}
+
+// AssignableID returns the ID an assignment targets to be on projectID, or on its phase
+// named phaseName when it isn't "".
+func (r *Resolver) AssignableID(projectID ProjectID, phaseName string) (AssignableID, error) {
+	if phaseName == "" {
+		return AssignableID(projectID), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.loadAssignables([]AssignableID{AssignableID(projectID)}); err != nil {
+		return 0, err
+	}
+
+	for _, p := range r.projects {
+		if p.ParentID == projectID && p.PhaseName == phaseName {
+			return AssignableID(p.ID), nil
+		}
+	}
+
+	return 0, fmt.Errorf("project %v, phase %q: %w", projectID, phaseName, ErrPhaseNotFound)
+}
+
+// AssignmentPhase returns the project an assignment is on and, when it targets one of
+// its phases, the phase. Both are nil for leave assignments and unknown assignables.
+func (r *Resolver) AssignmentPhase(a *Assignment) (project, phase *Project, err error) {
+	if a.baseAssignment == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err = r.loadAssignables([]AssignableID{a.AssignableID}); err != nil {
+		return
+	}
+
+	project = r.projects[a.AssignableID]
+	if project == nil || project.PhaseName == "" {
+		return
+	}
+	if parent, ok := r.projects[AssignableID(project.ParentID)]; ok {
+		project, phase = parent, project
+	}
+
+	return
+}
+
+// BillRate returns the rate of a bill rate ID. Bill rates are loaded per project, so
+// only IDs belonging to projects passed through HydrateAssignments or
+// HydrateTimeEntries are known.
+func (r *Resolver) BillRate(id int) (rate float64, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rate, ok = r.billRates[id]
+	return
+}
+
+// HydrateAssignments loads everything needed to resolve the users, assignables and
+// bill rates referenced by assignments.
+func (r *Resolver) HydrateAssignments(assignments *Assignments) error {
+	userIDs, assignableIDs := assignmentIDs(assignments)
+
+	return r.hydrate(userIDs, assignableIDs)
+}
+
+// HydrateTimeEntries loads everything needed to resolve the users, assignables and
+// bill rates referenced by time entries.
+func (r *Resolver) HydrateTimeEntries(timeEntries *TimeEntries) error {
+	userIDs, assignableIDs := []UserID{}, []AssignableID{}
+	for _, te := range timeEntries.Data {
+		userIDs = append(userIDs, te.UserID)
+		assignableIDs = append(assignableIDs, te.AssignableID)
+	}
+
+	return r.hydrate(userIDs, assignableIDs)
+}
+
+// ExpandAssignments sets the User, Project and LeaveType pointers of every assignment
+// from its UserID and AssignableID. Project is set for project and phase assignments,
+// LeaveType for leave assignments. Records are shared between assignments.
+func (r *Resolver) ExpandAssignments(assignments *Assignments) error {
+	userIDs, assignableIDs := assignmentIDs(assignments)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.loadUsers(userIDs); err != nil {
+		return err
+	}
+
+	if err := r.loadAssignables(assignableIDs); err != nil {
+		return err
+	}
+
+	for _, a := range assignments.Data {
+		a.User = r.users[a.UserID]
+		if a.baseAssignment != nil {
+			a.Project = r.projects[a.AssignableID]
+			a.LeaveType = r.leaveTypes[a.AssignableID]
+		}
+	}
+
+	return nil
+}
+
+func (r *Resolver) hydrate(userIDs []UserID, assignableIDs []AssignableID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.loadUsers(userIDs); err != nil {
+		return err
+	}
+
+	if err := r.loadAssignables(assignableIDs); err != nil {
+		return err
+	}
+
+	return r.loadBillRates(assignableIDs)
+}
+
+// loadUsers fetches all users unless they were fetched already or every id is known.
+func (r *Resolver) loadUsers(ids []UserID) error {
+	if r.usersLoaded || allUsersKnown(ids, r.users) {
+		return nil
+	}
+
+	users, _, err := r.c.GetAllUsers(map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	r.users = make(map[UserID]*User, len(users.Data))
+	for _, u := range users.Data {
+		r.users[u.ID] = u
+	}
+	r.usersLoaded = true
+
+	return nil
+}
+
+// loadAssignables fetches all projects including phases, and all leave types, unless
+// they were fetched already or every id is known.
+func (r *Resolver) loadAssignables(ids []AssignableID) error {
+	if r.assignablesLoaded || allAssignablesKnown(ids, r.projects, r.leaveTypes) {
+		return nil
+	}
+
+	projects, _, err := r.c.GetAllProjects(map[string]string{"with_phases": "true"})
+	if err != nil {
+		return err
+	}
+
+	leaveTypes, _, err := r.c.GetAllLeaveTypes(map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	r.projects = make(map[AssignableID]*Project, len(projects.Data))
+	for _, p := range projects.Data {
+		if p.baseProject == nil {
+			p.baseProject = &baseProject{}
+		}
+		r.projects[AssignableID(p.ID)] = p
+	}
+
+	r.leaveTypes = make(map[AssignableID]*LeaveType, len(leaveTypes.Data))
+	for _, lt := range leaveTypes.Data {
+		r.leaveTypes[AssignableID(lt.ID)] = lt
+	}
+	r.assignablesLoaded = true
+
+	return nil
+}
+
+// loadBillRates fetches the bill rates of every project among ids not loaded yet.
+func (r *Resolver) loadBillRates(ids []AssignableID) error {
+	if r.billRates == nil {
+		r.billRates = map[int]float64{}
+		r.billRatesLoaded = map[AssignableID]bool{}
+	}
+
+	for _, id := range ids {
+		if _, ok := r.projects[id]; r.billRatesLoaded[id] || !ok {
+			continue
+		}
+
+		billRates, _, err := r.c.GetAllProjectBillRates(ProjectID(id), map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		for _, br := range billRates.Data {
+			r.billRates[br.ID] = br.Rate
+		}
+		r.billRatesLoaded[id] = true
+	}
+
+	return nil
+}
+
+func assignmentIDs(assignments *Assignments) (userIDs []UserID, assignableIDs []AssignableID) {
+	userIDs, assignableIDs = []UserID{}, []AssignableID{}
+	for _, a := range assignments.Data {
+		userIDs = append(userIDs, a.UserID)
+		if a.baseAssignment != nil {
+			assignableIDs = append(assignableIDs, a.AssignableID)
+		}
+	}
+
+	return
+}
+
+func allUsersKnown(ids []UserID, users map[UserID]*User) bool {
+	for _, id := range ids {
+		if _, ok := users[id]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func allAssignablesKnown(ids []AssignableID, projects map[AssignableID]*Project, leaveTypes map[AssignableID]*LeaveType) bool {
+	for _, id := range ids {
+		_, isProject := projects[id]
+		_, isLeaveType := leaveTypes[id]
+		if !isProject && !isLeaveType {
+			return false
+		}
+	}
+
+	return true
+}
+
+func userName(u *User) string {
+	if u.DisplayName != "" || u.baseUser == nil {
+		return u.DisplayName
+	}
+
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
@@ -0,0 +1,95 @@
+package tenkft
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/users":                  `{"data": [{"id": 1, "display_name": "Ada Lovelace"}], "paging": {}}`,
+		"/projects":               `{"data": [{"id": 10, "name": "Engine"}, {"id": 11, "parent_id": 10, "phase_name": "Design"}], "paging": {}}`,
+		"/leave_types":            `{"data": [{"id": 20, "name": "Vacation"}], "paging": {}}`,
+		"/projects/10/bill_rates": `{"data": [{"id": 30, "rate": 150}], "paging": {}}`,
+		"/projects/11/bill_rates": `{"data": [], "paging": {}}`,
+	})
+	r := NewResolver(client)
+
+	assignments := &Assignments{Data: []*Assignment{
+		{UserID: 1, baseAssignment: &baseAssignment{AssignableID: 10}},
+		{UserID: 1, baseAssignment: &baseAssignment{AssignableID: 11}},
+		{UserID: 1, baseAssignment: &baseAssignment{AssignableID: 20}},
+	}}
+	if err := r.HydrateAssignments(assignments); err != nil {
+		t.Fatal("could not hydrate assignments", err)
+	}
+
+	if name, _ := r.UserName(1); name != "Ada Lovelace" {
+		t.Errorf("expected user name Ada Lovelace, got %q", name)
+	}
+	if name, _ := r.AssignableName(11); name != "Engine / Design" {
+		t.Errorf("expected phase name Engine / Design, got %q", name)
+	}
+	if name, _ := r.AssignableName(20); name != "Vacation" {
+		t.Errorf("expected leave type name Vacation, got %q", name)
+	}
+	if rate, ok := r.BillRate(30); !ok || rate != 150 {
+		t.Errorf("expected bill rate 150, got %v", rate)
+	}
+}
+
+func TestPhaseAssignables(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/projects":    `{"data": [{"id": 10, "name": "Engine"}, {"id": 11, "parent_id": 10, "phase_name": "Design"}, {"id": 12, "parent_id": 10, "phase_name": "Build"}], "paging": {}}`,
+		"/leave_types": `{"data": [{"id": 20, "name": "Vacation"}], "paging": {}}`,
+	})
+	r := NewResolver(client)
+
+	if id, err := r.AssignableID(10, "Build"); err != nil || id != 12 {
+		t.Errorf("expected phase Build to be 12, got %v, %v", id, err)
+	}
+	if id, err := r.AssignableID(10, ""); err != nil || id != 10 {
+		t.Errorf("expected the project itself to be 10, got %v, %v", id, err)
+	}
+	if _, err := r.AssignableID(10, "Launch"); !errors.Is(err, ErrPhaseNotFound) {
+		t.Errorf("expected ErrPhaseNotFound, got %v", err)
+	}
+
+	project, phase, err := r.AssignmentPhase(&Assignment{baseAssignment: &baseAssignment{AssignableID: 11}})
+	if err != nil || project == nil || project.ID != 10 || phase == nil || phase.PhaseName != "Design" {
+		t.Errorf("expected phase Design of project 10, got %+v, %+v, %v", project, phase, err)
+	}
+	project, phase, _ = r.AssignmentPhase(&Assignment{baseAssignment: &baseAssignment{AssignableID: 10}})
+	if project == nil || project.ID != 10 || phase != nil {
+		t.Errorf("expected project 10 without phase, got %+v, %+v", project, phase)
+	}
+	if project, phase, _ = r.AssignmentPhase(&Assignment{baseAssignment: &baseAssignment{AssignableID: 20}}); project != nil || phase != nil {
+		t.Errorf("expected no project for a leave assignment, got %+v, %+v", project, phase)
+	}
+}
+
+func TestAssignmentExpansion(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/users":               `{"data": [{"id": 1, "display_name": "Ada Lovelace"}], "paging": {}}`,
+		"/projects":            `{"data": [{"id": 10, "name": "Engine"}], "paging": {}}`,
+		"/leave_types":         `{"data": [{"id": 20, "name": "Vacation"}], "paging": {}}`,
+		"/users/1/assignments": `{"data": [{"id": 5, "user_id": 1, "assignable_id": 10}, {"id": 6, "user_id": 1, "assignable_id": 20}], "paging": {}}`,
+	})
+	client.expander = NewResolver(client)
+
+	assignments, _, err := client.GetUserAssignments(&User{ID: 1}, map[string]string{})
+	if err != nil {
+		t.Fatal("could not get user assignments", err)
+	}
+
+	project, leave := assignments.Data[0], assignments.Data[1]
+	if project.User == nil || project.User.DisplayName != "Ada Lovelace" {
+		t.Error("expected the assignment user to be expanded")
+	}
+	if project.Project == nil || project.Project.Name != "Engine" {
+		t.Error("expected the assignment project to be expanded")
+	}
+	if leave.Project != nil || leave.LeaveType == nil || leave.LeaveType.Name != "Vacation" {
+		t.Error("expected the leave assignment to be expanded into a leave type")
+	}
+}
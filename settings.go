@@ -0,0 +1,66 @@
+package tenkft
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Settings holds the settings 10000ft sends on projects, phases and users. They are
+// undocumented and their shape varies: users and most projects get a bit field such as
+// 0 or 3, some accounts send objects. The JSON is kept as received so it round-trips
+// unchanged on updates, use Int or Decode to read it.
+type Settings struct {
+	raw json.RawMessage
+}
+
+// Raw returns the settings as received, nil if they were absent.
+func (s Settings) Raw() json.RawMessage {
+	return s.raw
+}
+
+// IsZero reports whether the settings are absent or null.
+func (s Settings) IsZero() bool {
+	return len(s.raw) == 0 || bytes.Equal(s.raw, []byte("null"))
+}
+
+// Int returns the settings as a bit field, ok is false when they aren't a number.
+func (s Settings) Int() (n int64, ok bool) {
+	var number json.Number
+	if err := json.Unmarshal(s.raw, &number); err != nil {
+		return
+	}
+
+	n, err := number.Int64()
+	ok = err == nil
+	return
+}
+
+// Has reports whether flag is set in the settings bit field.
+func (s Settings) Has(flag int64) bool {
+	n, ok := s.Int()
+	return ok && n&flag == flag
+}
+
+// Decode decodes the settings into v, leaving it untouched when they are absent.
+func (s Settings) Decode(v interface{}) error {
+	if s.IsZero() {
+		return nil
+	}
+
+	return json.Unmarshal(s.raw, v)
+}
+
+// MarshalJSON encodes the settings as received, null when absent.
+func (s Settings) MarshalJSON() ([]byte, error) {
+	if len(s.raw) == 0 {
+		return []byte("null"), nil
+	}
+
+	return s.raw, nil
+}
+
+// UnmarshalJSON keeps a copy of b.
+func (s *Settings) UnmarshalJSON(b []byte) error {
+	s.raw = append(json.RawMessage(nil), b...)
+	return nil
+}
@@ -0,0 +1,44 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	var u User
+	if err := json.Unmarshal([]byte(`{"id": 1, "user_settings": 3}`), &u); err != nil {
+		t.Fatal("could not decode user", err)
+	}
+	if n, ok := u.UserSettings.Int(); !ok || n != 3 || !u.UserSettings.Has(2) || u.UserSettings.Has(4) {
+		t.Errorf("expected the user settings bit field 3, got %s", u.UserSettings.Raw())
+	}
+
+	var p Project
+	if err := json.Unmarshal([]byte(`{"id": 1, "settings": {"bill_rate": 150}}`), &p); err != nil {
+		t.Fatal("could not decode project", err)
+	}
+	if _, ok := p.Settings.Int(); ok {
+		t.Errorf("expected object settings not to be a bit field")
+	}
+
+	var settings struct {
+		BillRate float64 `json:"bill_rate"`
+	}
+	if err := p.Settings.Decode(&settings); err != nil || settings.BillRate != 150 {
+		t.Errorf("expected a bill rate of 150, got %+v, %v", settings, err)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal("could not encode project", err)
+	}
+	var encoded map[string]json.RawMessage
+	json.Unmarshal(b, &encoded)
+	if string(encoded["settings"]) != `{"bill_rate":150}` {
+		t.Errorf("expected the settings to round-trip unchanged, got %s", encoded["settings"])
+	}
+	if !(Phase{}).Settings.IsZero() {
+		t.Errorf("expected absent settings to be zero")
+	}
+}
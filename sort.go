@@ -0,0 +1,152 @@
+package tenkft
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortBy sorts projects with less, ties are ordered by ID so that the order doesn't
+// depend on the order pages came back in.
+func (ps *Projects) SortBy(less func(a, b *Project) bool) {
+	sort.SliceStable(ps.Data, func(i, j int) bool {
+		a, b := ps.Data[i], ps.Data[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID < b.ID
+	})
+}
+
+// SortByName sorts projects by name, case insensitively.
+func (ps *Projects) SortByName() {
+	ps.SortBy(func(a, b *Project) bool { return projectName(a) < projectName(b) })
+}
+
+// SortByStartDate sorts projects by start date, projects without one first.
+func (ps *Projects) SortByStartDate() {
+	ps.SortBy(func(a, b *Project) bool { return projectStartsAt(a) < projectStartsAt(b) })
+}
+
+// SortByCreatedAt sorts projects by creation time.
+func (ps *Projects) SortByCreatedAt() {
+	ps.SortBy(func(a, b *Project) bool { return a.CreatedAt < b.CreatedAt })
+}
+
+// SortByScheduledHours sorts projects by scheduled hours, most first.
+func (ps *Projects) SortByScheduledHours() {
+	ps.SortBy(func(a, b *Project) bool { return a.ScheduledHours > b.ScheduledHours })
+}
+
+// SortBy sorts users with less, ties are ordered by ID, see Projects.SortBy.
+func (users *Users) SortBy(less func(a, b *User) bool) {
+	sort.SliceStable(users.Data, func(i, j int) bool {
+		a, b := users.Data[i], users.Data[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID < b.ID
+	})
+}
+
+// SortByName sorts users by display name, case insensitively.
+func (users *Users) SortByName() {
+	users.SortBy(func(a, b *User) bool { return strings.ToLower(a.DisplayName) < strings.ToLower(b.DisplayName) })
+}
+
+// SortByStartDate sorts users by hire date, users without one first.
+func (users *Users) SortByStartDate() {
+	users.SortBy(func(a, b *User) bool { return userHireDate(a) < userHireDate(b) })
+}
+
+// SortByCreatedAt sorts users by creation time.
+func (users *Users) SortByCreatedAt() {
+	users.SortBy(func(a, b *User) bool { return a.CreatedAt < b.CreatedAt })
+}
+
+// SortBy sorts assignments with less, ties are ordered by ID, see Projects.SortBy.
+func (as *Assignments) SortBy(less func(a, b *Assignment) bool) {
+	sort.SliceStable(as.Data, func(i, j int) bool {
+		a, b := as.Data[i], as.Data[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID < b.ID
+	})
+}
+
+// SortByStartDate sorts assignments by start date.
+func (as *Assignments) SortByStartDate() {
+	as.SortBy(func(a, b *Assignment) bool { return assignmentStartsAt(a) < assignmentStartsAt(b) })
+}
+
+// SortByCreatedAt sorts assignments by creation time.
+func (as *Assignments) SortByCreatedAt() {
+	as.SortBy(func(a, b *Assignment) bool { return a.CreatedAt < b.CreatedAt })
+}
+
+// SortByHours sorts assignments by hours per day, most first. Fixed and percent
+// allocations are compared on FixedHours and Percent among themselves and come after.
+func (as *Assignments) SortByHours() {
+	hours := func(a *Assignment) (rank int, hours float64) {
+		if a.baseAssignment == nil {
+			return 3, 0
+		}
+		switch a.AllocationMode {
+		case AllocationFixed:
+			return 1, a.FixedHours
+		case AllocationPercent:
+			return 2, a.Percent
+		}
+		return 0, a.HoursPerDay
+	}
+
+	as.SortBy(func(a, b *Assignment) bool {
+		am, ah := hours(a)
+		bm, bh := hours(b)
+		if am != bm {
+			return am < bm
+		}
+		return ah > bh
+	})
+}
+
+func projectName(p *Project) string {
+	if p.baseProject == nil {
+		return ""
+	}
+
+	return strings.ToLower(p.Name)
+}
+
+func projectStartsAt(p *Project) string {
+	if p.baseProject == nil {
+		return ""
+	}
+
+	return p.StartsAt
+}
+
+func userHireDate(u *User) string {
+	if u.baseUser == nil {
+		return ""
+	}
+
+	return u.HireDate
+}
+
+func assignmentStartsAt(a *Assignment) string {
+	if a.baseAssignment == nil {
+		return ""
+	}
+
+	return a.StartsAt
+}
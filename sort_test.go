@@ -0,0 +1,46 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	var projects Projects
+	json.Unmarshal([]byte(`{"data": [
+		{"id": 3, "name": "beta", "starts_at": "2018-01-01"},
+		{"id": 2, "name": "Alpha", "starts_at": "2018-02-01"},
+		{"id": 1, "name": "beta", "starts_at": "2018-03-01"}
+	]}`), &projects)
+
+	ids := func() string {
+		s := ""
+		for _, p := range projects.Data {
+			s += fmt.Sprint(p.ID)
+		}
+		return s
+	}
+
+	projects.SortByName()
+	if ids() != "213" {
+		t.Errorf("expected alpha then both betas by ID, got %v", ids())
+	}
+
+	projects.SortByStartDate()
+	if ids() != "321" {
+		t.Errorf("expected projects by start date, got %v", ids())
+	}
+
+	var assignments Assignments
+	json.Unmarshal([]byte(`{"data": [
+		{"id": 1, "allocation_mode": "percent", "percent": 0.5},
+		{"id": 2, "allocation_mode": "hours_per_day", "hours_per_day": 4},
+		{"id": 3, "allocation_mode": "hours_per_day", "hours_per_day": 8}
+	]}`), &assignments)
+
+	assignments.SortByHours()
+	if a := assignments.Data; a[0].ID != 3 || a[1].ID != 2 || a[2].ID != 1 {
+		t.Errorf("expected 8 then 4 hours per day then percent, got %v %v %v", a[0].ID, a[1].ID, a[2].ID)
+	}
+}
@@ -0,0 +1,112 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// staleKey is the context key the time a stale response was cached at is stored
+// under, see Stale.
+type staleKey struct{}
+
+// WithStaleFallback keeps the body of every successful GET in memory and serves it
+// back, for up to maxAge, when 10000ft is unreachable or failing with a 5xx status,
+// so that dashboards keep rendering during incidents. Use Stale to tell such
+// responses apart. Entries are kept per URL and dropped once older than maxAge.
+func WithStaleFallback(maxAge time.Duration) ClientOption {
+	return func(c *Client) {
+		c.fallback = &staleCache{maxAge: maxAge, entries: map[string]staleEntry{}}
+	}
+}
+
+// Stale reports whether resp was served from the WithStaleFallback cache instead of
+// 10000ft, and when it was cached.
+func Stale(resp *http.Response) (cachedAt time.Time, ok bool) {
+	if resp == nil || resp.Request == nil {
+		return
+	}
+
+	cachedAt, ok = resp.Request.Context().Value(staleKey{}).(time.Time)
+
+	return
+}
+
+type staleEntry struct {
+	body     []byte
+	header   http.Header
+	cachedAt time.Time
+}
+
+// staleCache holds the last successful response of every GET URL.
+type staleCache struct {
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	entries map[string]staleEntry
+	sweptAt time.Time
+}
+
+// store caches the body of a successful GET of key. Entries older than maxAge are
+// swept at most once per maxAge, so that URLs no longer fetched don't pile up.
+func (s *staleCache) store(key string, body []byte, resp *http.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.sweptAt) > s.maxAge {
+		for k, e := range s.entries {
+			if now.Sub(e.cachedAt) > s.maxAge {
+				delete(s.entries, k)
+			}
+		}
+		s.sweptAt = now
+	}
+
+	s.entries[key] = staleEntry{body: body, header: resp.Header.Clone(), cachedAt: now}
+}
+
+// serve returns the cached body for key and a response marked as stale, or ok false
+// when there is no entry younger than maxAge.
+func (s *staleCache) serve(ctx context.Context, key string) (body []byte, resp *http.Response, ok bool) {
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	expired := ok && time.Since(e.cachedAt) > s.maxAge
+	if expired {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+
+	if !ok || expired {
+		return nil, nil, false
+	}
+
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, staleKey{}, e.cachedAt), http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	resp = &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     e.header.Clone(),
+		Body:       http.NoBody,
+		Request:    req,
+	}
+
+	return e.body, resp, true
+}
+
+// unreachable reports whether a failed call is worth falling back on a stale response
+// for: no response at all or a server error, unless the caller gave up.
+func unreachable(ctx context.Context, resp *http.Response, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	return resp == nil || resp.StatusCode == 0 || resp.StatusCode >= 500
+}
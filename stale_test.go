@@ -0,0 +1,76 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaleFallback(t *testing.T) {
+	down := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1, "name": "Engine"}], "paging": {"page": 1}}`)
+	}))
+	defer srv.Close()
+
+	client := &Client{token: "test", env: srv.URL}
+	WithStaleFallback(time.Hour)(client)
+
+	opts := map[string]string{"page": "1"}
+	_, resp, err := client.GetProjects(opts)
+	if err != nil {
+		t.Fatal("could not get projects", err)
+	}
+	if _, ok := Stale(resp); ok {
+		t.Error("expected a live response not to be stale")
+	}
+
+	down = true
+	projects, resp, err := client.GetProjects(opts)
+	if err != nil {
+		t.Fatal("expected the stale projects to be served, got", err)
+	}
+	if len(projects.Data) != 1 || projects.Data[0].Name != "Engine" {
+		t.Errorf("expected the cached project, got %+v", projects.Data)
+	}
+	if cachedAt, ok := Stale(resp); !ok || time.Since(cachedAt) > time.Minute {
+		t.Errorf("expected the response to be marked stale, got %v %v", cachedAt, ok)
+	}
+	if resp.Body == nil {
+		t.Error("expected the stale response to have a body")
+	}
+
+	if _, _, err := client.GetProjects(map[string]string{"page": "2"}); err == nil {
+		t.Error("expected an uncached page to fail while down")
+	}
+}
+
+func TestStaleCacheExpiry(t *testing.T) {
+	s := &staleCache{maxAge: time.Hour, entries: map[string]staleEntry{}}
+	resp := &http.Response{Header: http.Header{}}
+	s.store("/old", []byte("old"), resp)
+	s.store("/older", []byte("older"), resp)
+
+	old := s.entries["/old"]
+	old.cachedAt = time.Now().Add(-2 * time.Hour)
+	s.entries["/old"], s.entries["/older"] = old, old
+	if _, _, ok := s.serve(context.Background(), "/old"); ok {
+		t.Error("expected an entry past maxAge not to be served")
+	}
+	if _, ok := s.entries["/old"]; ok {
+		t.Error("expected the expired entry to be dropped when served")
+	}
+
+	s.sweptAt = time.Now().Add(-2 * time.Hour)
+	s.store("/new", []byte("new"), resp)
+	if _, ok := s.entries["/older"]; ok || len(s.entries) != 1 {
+		t.Errorf("expected expired entries to be swept on store, got %v", s.entries)
+	}
+}
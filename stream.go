@@ -0,0 +1,39 @@
+package tenkft
+
+import "context"
+
+// StreamAllProjects sends every project on the returned channel as its page arrives,
+// see Endpoint.Stream.
+func (c *Client) StreamAllProjects(ctx context.Context, opts map[string]string) (<-chan *Project, <-chan error) {
+	return projectsEndpoint.Stream(ctx, c, opts)
+}
+
+// StreamAllUsers sends every user on the returned channel as its page arrives, enriched
+// like GetAllUsers, see Endpoint.Stream.
+func (c *Client) StreamAllUsers(ctx context.Context, opts map[string]string) (<-chan *User, <-chan error) {
+	return usersEndpoint.stream(ctx, c, opts, nil, func(users []*User) error {
+		return c.enrich(ctx, users...)
+	})
+}
+
+// StreamAllUserAssignments sends every assignment of u on the returned channel as its
+// page arrives, expanded like GetAllUserAssignments, see Endpoint.Stream.
+func (c *Client) StreamAllUserAssignments(ctx context.Context, u *User, opts map[string]string) (<-chan *Assignment, <-chan error) {
+	return userAssignmentsEndpoint.stream(ctx, c, opts, []int{int(u.ID)}, c.expandPage)
+}
+
+// StreamAllProjectAssignments sends every assignment on p on the returned channel as
+// its page arrives, expanded like GetProjectAssignments, see Endpoint.Stream.
+func (c *Client) StreamAllProjectAssignments(ctx context.Context, p *Project, opts map[string]string) (<-chan *Assignment, <-chan error) {
+	return projectAssignmentsEndpoint.stream(ctx, c, opts, []int{int(p.ID)}, c.expandPage)
+}
+
+// StreamAllTimeEntries sends every time entry on the returned channel as its page
+// arrives, see Endpoint.Stream.
+func (c *Client) StreamAllTimeEntries(ctx context.Context, opts map[string]string) (<-chan *TimeEntry, <-chan error) {
+	return timeEntriesEndpoint.Stream(ctx, c, opts)
+}
+
+func (c *Client) expandPage(assignments []*Assignment) error {
+	return c.expand(&Assignments{Data: assignments})
+}
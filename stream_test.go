@@ -0,0 +1,62 @@
+package tenkft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamAllUsers(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			<-release
+			fmt.Fprint(w, `{"data": [{"id": 3}], "paging": {"page": 2}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": 1, "next": "/users?page=2"}}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	users, errs := client.StreamAllUsers(context.Background(), map[string]string{})
+
+	// The first page is delivered while the second one is still pending.
+	if u := <-users; u.ID != 1 {
+		t.Errorf("expected user 1 first, got %v", u.ID)
+	}
+	close(release)
+
+	ids := []UserID{}
+	for u := range users {
+		ids = append(ids, u.ID)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal("could not stream users", err)
+	}
+	if len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("expected users 2 and 3, got %v", ids)
+	}
+}
+
+func TestStreamCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {"page": 1, "next": "/projects?page=2"}}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	projects, errs := client.StreamAllProjects(ctx, map[string]string{})
+	<-projects
+	cancel()
+
+	for range projects {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package tenkft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// TeamSync lists the changes SyncProjectTeam made.
+type TeamSync struct {
+	// Added are the assignments created for new members.
+	Added []*Assignment
+	// Ended are the assignments of former members cut short to end yesterday.
+	Ended []*Assignment
+	// Deleted are the assignments of former members that had not started yet.
+	Deleted []*Assignment
+}
+
+// SyncProjectTeam converges the members of a project, as returned by GetProjectUsers,
+// on desiredUserIDs. New members get a full time assignment from today until the
+// project ends, to be adjusted afterwards. Former members have their current
+// assignments ended yesterday and their future ones deleted, past ones are kept.
+// On error sync holds the changes made so far.
+func (c *Client) SyncProjectTeam(projectID ProjectID, desiredUserIDs []UserID) (sync *TeamSync, resp *http.Response, err error) {
+	return c.SyncProjectTeamCtx(context.Background(), projectID, desiredUserIDs)
+}
+
+// SyncProjectTeamCtx is SyncProjectTeam honouring ctx.
+func (c *Client) SyncProjectTeamCtx(ctx context.Context, projectID ProjectID, desiredUserIDs []UserID) (sync *TeamSync, resp *http.Response, err error) {
+	sync = &TeamSync{}
+
+	project, resp, err := projectsEndpoint.Get(ctx, c, int(projectID), map[string]string{})
+	if err != nil {
+		return
+	}
+
+	users, resp, err := projectUsersEndpoint.ListAll(ctx, c, map[string]string{}, int(projectID))
+	if err != nil {
+		return
+	}
+
+	members := map[UserID]bool{}
+	for _, u := range users.Data {
+		members[u.ID] = true
+	}
+	desired := map[UserID]bool{}
+	for _, id := range desiredUserIDs {
+		desired[id] = true
+	}
+
+	today := c.FormatDate(c.Today())
+	yesterday := c.FormatDate(c.Today().AddDate(0, 0, -1))
+
+	for _, id := range desiredUserIDs {
+		if members[id] {
+			continue
+		}
+		members[id] = true
+
+		endsAt := project.EndsAt
+		if endsAt < today {
+			endsAt = today
+		}
+
+		a := &Assignment{
+			baseAssignment: &baseAssignment{
+				AllocationMode: AllocationPercent,
+				AssignableID:   AssignableID(projectID),
+				Percent:        1,
+				StartsAt:       today,
+				EndsAt:         endsAt,
+			},
+			UserID: id,
+		}
+		if resp, err = c.CreateUserAssignmentCtx(ctx, a); err != nil {
+			return
+		}
+		sync.Added = append(sync.Added, a)
+	}
+
+	leaving := false
+	for _, u := range users.Data {
+		leaving = leaving || !desired[u.ID]
+	}
+	if !leaving {
+		return
+	}
+
+	assignments, resp, err := projectAssignmentsEndpoint.ListAll(ctx, c, map[string]string{"from": today}, int(projectID))
+	if err != nil {
+		return
+	}
+
+	for _, a := range assignments.Data {
+		if desired[a.UserID] || a.EndsAt < today {
+			continue
+		}
+
+		if a.StartsAt >= today {
+			if resp, err = c.DeleteUserAssignmentCtx(ctx, a); err != nil {
+				return
+			}
+			sync.Deleted = append(sync.Deleted, a)
+			continue
+		}
+
+		a.EndsAt = yesterday
+		if resp, err = c.UpdateUserAssignmentCtx(ctx, a); err != nil {
+			return
+		}
+		sync.Ended = append(sync.Ended, a)
+	}
+
+	return
+}
+
+// FillPlaceholder replaces the placeholder assignment with the same booking for the
+// user with the given ID: the user assignment is created first, then the placeholder's
+// deleted. If the deletion fails the user assignment is deleted again so that nothing
+// is booked twice, err then reports both failures if the rollback fails too.
+func (c *Client) FillPlaceholder(placeholder *Assignment, userID UserID) (filled *Assignment, resp *http.Response, err error) {
+	return c.FillPlaceholderCtx(context.Background(), placeholder, userID)
+}
+
+// FillPlaceholderCtx is FillPlaceholder honouring ctx.
+func (c *Client) FillPlaceholderCtx(ctx context.Context, placeholder *Assignment, userID UserID) (filled *Assignment, resp *http.Response, err error) {
+	filled = NewAssignment()
+	*filled.baseAssignment = *placeholder.baseAssignment
+	filled.UserID = userID
+
+	if resp, err = c.CreateUserAssignmentCtx(ctx, filled); err != nil {
+		return nil, resp, err
+	}
+
+	if resp, err = c.DeleteUserAssignmentCtx(ctx, placeholder); err != nil {
+		if _, rollbackErr := c.DeleteUserAssignmentCtx(ctx, filled); rollbackErr != nil {
+			err = fmt.Errorf("%w, and rolling back assignment %d failed: %v", err, filled.ID, rollbackErr)
+		}
+		return nil, resp, err
+	}
+
+	return
+}
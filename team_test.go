@@ -0,0 +1,104 @@
+package tenkft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSyncProjectTeam(t *testing.T) {
+	client := &Client{token: "test", weekStart: time.Monday}
+	day := func(offset int) string {
+		return client.FormatDate(client.Today().AddDate(0, 0, offset))
+	}
+
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		call := r.Method + " " + r.URL.Path
+		switch call {
+		case "GET /projects/7":
+			fmt.Fprintf(w, `{"id": 7, "ends_at": %q}`, day(30))
+		case "GET /projects/7/users":
+			fmt.Fprint(w, `{"data": [{"id": 1}, {"id": 2}], "paging": {}}`)
+		case "GET /projects/7/assignments":
+			fmt.Fprintf(w, `{"data": [
+				{"id": 10, "user_id": 1, "assignable_id": 7, "starts_at": %q, "ends_at": %q},
+				{"id": 20, "user_id": 2, "assignable_id": 7, "starts_at": %q, "ends_at": %q},
+				{"id": 21, "user_id": 2, "assignable_id": 7, "starts_at": %q, "ends_at": %q}
+			], "paging": {}}`, day(-10), day(10), day(-10), day(10), day(5), day(20))
+		default:
+			var a map[string]interface{}
+			json.Unmarshal(body, &a)
+			calls = append(calls, fmt.Sprintf("%v %v", call, a["ends_at"]))
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer srv.Close()
+	client.env = srv.URL
+
+	sync, _, err := client.SyncProjectTeam(7, []UserID{1, 3})
+	if err != nil {
+		t.Fatal("could not sync the team", err)
+	}
+
+	if len(sync.Added) != 1 || sync.Added[0].UserID != 3 || sync.Added[0].EndsAt != day(30) {
+		t.Errorf("expected user 3 to be added until the project ends, got %+v", sync.Added)
+	}
+	if len(sync.Ended) != 1 || sync.Ended[0].ID != 20 || len(sync.Deleted) != 1 || sync.Deleted[0].ID != 21 {
+		t.Errorf("expected user 2's assignments to be ended and deleted, got %+v %+v", sync.Ended, sync.Deleted)
+	}
+
+	sort.Strings(calls)
+	expected := []string{
+		"DELETE /users/2/assignments/21 <nil>",
+		"POST /users/3/assignments " + day(30),
+		"PUT /users/2/assignments/20 " + day(-1),
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestFillPlaceholder(t *testing.T) {
+	failDelete := false
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost:
+			fmt.Fprint(w, `{"id": 11, "user_id": 5}`)
+		case r.URL.Path == "/users/900/assignments/10" && failDelete:
+			http.Error(w, "nope", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	placeholder := NewAssignment()
+	placeholder.ID, placeholder.UserID, placeholder.AssignableID = 10, 900, 7
+
+	filled, _, err := client.FillPlaceholder(placeholder, 5)
+	if err != nil {
+		t.Fatal("could not fill the placeholder", err)
+	}
+	if filled.ID != 11 || filled.AssignableID != 7 {
+		t.Errorf("expected the new assignment on project 7, got %+v", filled)
+	}
+
+	calls, failDelete = nil, true
+	if _, _, err := client.FillPlaceholder(placeholder, 5); err == nil {
+		t.Error("expected the failed deletion to be reported")
+	}
+	expected := "[POST /users/5/assignments DELETE /users/900/assignments/10 DELETE /users/5/assignments/11]"
+	if fmt.Sprint(calls) != expected {
+		t.Errorf("expected the user assignment to be rolled back, got %v", calls)
+	}
+}
@@ -0,0 +1,75 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
+)
+
+// retryStatsKey is the context key the retry stats are stored under in the request of
+// a response.
+type retryStatsKey struct{}
+
+// RetryHook is called after every API call with its method, its endpoint, e.g.
+// "/projects/%d/phases", and the retries it went through. Use it to measure how often
+// each endpoint is throttled.
+type RetryHook func(method, endpoint string, stats utils.RetryStats)
+
+// WithRetryHook sets a RetryHook called after every API call.
+func WithRetryHook(hook RetryHook) ClientOption {
+	return func(c *Client) {
+		c.retryHook = hook
+	}
+}
+
+// Retries returns the retries done for the call resp belongs to, the zero value if
+// resp doesn't come from the client.
+func Retries(resp *http.Response) utils.RetryStats {
+	if resp == nil || resp.Request == nil {
+		return utils.RetryStats{}
+	}
+
+	stats, _ := resp.Request.Context().Value(retryStatsKey{}).(utils.RetryStats)
+
+	return stats
+}
+
+// observe reports stats to the retry hook and attaches them to resp for Retries, and
+// checks took against the latency budget of the endpoint.
+func (c *Client) observe(method, rawURL string, resp *http.Response, stats utils.RetryStats, took time.Duration) {
+	if resp != nil && resp.Request != nil {
+		resp.Request = resp.Request.WithContext(context.WithValue(resp.Request.Context(), retryStatsKey{}, stats))
+	}
+
+	if c.retryHook == nil && c.latency.hook == nil {
+		return
+	}
+
+	endpoint := endpointOf(c.env, rawURL)
+	if c.retryHook != nil {
+		c.retryHook(method, endpoint, stats)
+	}
+	c.latency.check(method, endpoint, took)
+}
+
+// endpointOf returns the path of rawURL relative to env with IDs replaced by %d, so
+// that calls to the same endpoint share a name.
+func endpointOf(env, rawURL string) string {
+	path := strings.TrimPrefix(rawURL, env)
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s != "" && strings.Trim(s, "0123456789") == "" {
+			segments[i] = "%d"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -21,15 +21,21 @@
 //
 // You can also use MaxRetries to automatically retry a request when the tenkft API
 // returns an error.
+//
+// Tools can share their configuration through named profiles and environment
+// variables, see Profile and NewClientFromEnv. Profiles files are JSON rather than
+// YAML, the package depending on the standard library only.
 package tenkft
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/workco/go-tenkft/utils"
 )
@@ -39,889 +45,1097 @@ const (
 	Production = "https://api.10000ft.com/api/v1"
 	// Staging environment URL
 	Staging = "https://vnext.10000ft.com/api/v1"
+	// ResourceManagement is the URL 10000ft is served under since it became
+	// Smartsheet Resource Management, tokens work against both.
+	ResourceManagement = "https://api.rm.smartsheet.com/api/v1"
+	// ResourceManagementEU is the Resource Management URL of accounts hosted in the EU.
+	ResourceManagementEU = "https://api.rm.smartsheet.eu/api/v1"
+
+	defaultPageRetries = 3
 )
 
+// pageRetryBackoff is the initial wait before retrying a page, it doubles on every attempt.
+var pageRetryBackoff = 2 * time.Second
+
 // Client use NewClient to return this instance type.
 type Client struct {
 	token      string
 	env        string
 	MaxRetries int
+	// PageRetries is the number of times GetAll* methods retry a single page that
+	// failed with a 5xx status, on top of the retries done by MaxRetries.
+	PageRetries int
+	// OnProgress, when set, is called after every page fetched by GetAll* methods and
+	// export sources, so that long runs can render progress.
+	OnProgress ProgressFunc
+
+	expander  *Resolver
+	location  *time.Location
+	weekStart time.Weekday
+	redacted  map[string]bool
+	currency  string
+	useNumber bool
+	retryHook RetryHook
+	latency   latencyBudgets
+	flights   *flightGroup
+	fallback  *staleCache
+	enrichers []Enricher
+	version   APIVersion
+	limits    Limits
+	adaptive  *AdaptivePaging
+	// requeueBackoff is the wait before UpdateMany sends a throttled item again,
+	// defaultRequeueBackoff for clients made by NewClient.
+	requeueBackoff time.Duration
+	// capabilities holds what Capabilities found out about the account.
+	capabilities capabilitySet
+	// httpClient sends the requests, see WithHTTPClient.
+	httpClient *http.Client
+	// auth sets the token on requests, HeaderAuth(defaultAuthHeader) when nil.
+	auth Auth
 }
 
-// NewClient takes credentials and returns client to perform API operations on
-func NewClient(token, env string) (*Client, error) {
-	if env != Production && env != Staging {
-		return &Client{}, fmt.Errorf("env must be either %v, or %v", Production, Staging)
-	}
+// ProgressFunc reports that fetched items have been fetched so far, page being the
+// latest page. total is the number of items expected, 0 when unknown: list responses
+// don't include it, see CountProjects and CountUsers to find it out beforehand.
+type ProgressFunc func(fetched, total, page int)
 
-	c := &Client{token: token, env: env}
+// environments are the URLs accepted by NewClient, in the order NewClientWithCheck
+// tries them.
+var environments = []string{Production, ResourceManagement, ResourceManagementEU, Staging}
 
-	return c, nil
-}
+// defaultAuthHeader is the header the token is sent in unless WithAuth says otherwise.
+const defaultAuthHeader = "auth"
 
-func queryfy(opts map[string]string) string {
-	querySlice := []string{}
-	for k, val := range opts {
-		querySlice = append(querySlice, k+"="+val)
-	}
+// ErrInvalidToken is returned by NewClientWithCheck when the API rejects the token.
+// errors.Is also matches it against the APIError of any call answered with a 401.
+var ErrInvalidToken = errors.New("tenkft: the API rejected the token")
 
-	return strings.Join(querySlice, "&")
-}
+// ErrUserNotFound is returned by FindUserByEmail when no user has the email.
+var ErrUserNotFound = errors.New("tenkft: user not found")
 
-// GetAllProjects returns all projects - automatically paginates and returns accumulated projects.
-// resp and err correspond to the latest one in the loop.
-func (c *Client) GetAllProjects(opts map[string]string) (projects *Projects, resp *http.Response, err error) {
-	projects = &Projects{Paging: &Paging{}}
-	opts["per_page"] = "201"
-	projects, resp, err = c.GetProjects(opts)
-	if err != nil {
-		return
-	}
+// ErrNoAllocation is returned by UpdateUserAssignmentRepetition when the assignment
+// has no allocation to apply, e.g. one that wasn't built with NewAssignment.
+var ErrNoAllocation = errors.New("tenkft: assignment without allocation")
 
-	for loop := projects.Paging.HasNext(); loop == true; loop = projects.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(projects.Paging.GetNextPage())
-		newProjects, newResp, newErr := c.GetProjects(opts)
-		resp = newResp
-		if err != nil {
-			err = newErr
-			break
-		}
+// repetitionYears is how many years around an occurrence the other occurrences of its
+// repetition are looked for, the API only listing the coming weeks by default.
+const repetitionYears = 10
 
-		projects.Paging = newProjects.Paging
-		projects.Data = append(projects.Data, newProjects.Data...)
-	}
+// ClientOption configures optional Client settings, see NewClient and NewClientWithCheck.
+type ClientOption func(*Client)
 
-	return
+// WithEnv sets the environment the client talks to, one of Production, Staging,
+// ResourceManagement or ResourceManagementEU.
+func WithEnv(env string) ClientOption {
+	return func(c *Client) {
+		c.env = env
+	}
 }
 
-// GetProjects returns all projects with default pagination
-func (c *Client) GetProjects(opts map[string]string) (projects *Projects, resp *http.Response, err error) {
-	projects = &Projects{Paging: &Paging{}}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/projects?"+query, http.MethodGet, map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// WithMaxRetries sets MaxRetries on the client.
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
 	}
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// WithPageRetries sets PageRetries on the client.
+func WithPageRetries(pageRetries int) ClientOption {
+	return func(c *Client) {
+		c.PageRetries = pageRetries
 	}
-	defer resp.Body.Close()
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
+// WithProgress sets OnProgress on the client.
+func WithProgress(f ProgressFunc) ClientOption {
+	return func(c *Client) {
+		c.OnProgress = f
 	}
+}
 
-	err = json.Unmarshal(data, projects)
-	if err != nil {
-		return
+// WithHTTPClient makes the client send its requests through hc, e.g. to set timeouts,
+// proxies or connection pooling. A new http.Client without timeout is used per request
+// otherwise.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
 	}
-
-	return
 }
 
-// GetTimeEntries returns all time entries with default pagination
-func (c *Client) GetTimeEntries(opts map[string]string) (timeEntries *TimeEntries, resp *http.Response, err error) {
-	timeEntries = &TimeEntries{Paging: &Paging{}}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/time_entries?"+query, http.MethodGet, map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// WithTransport makes the client send its requests through rt, e.g. a test transport,
+// see WithHTTPClient.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient = &http.Client{Transport: rt}
 	}
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// WithAssignmentExpansion makes every assignment fetch expand the User, Project and
+// LeaveType pointers of the returned assignments through r, see Resolver.ExpandAssignments.
+func WithAssignmentExpansion(r *Resolver) ClientOption {
+	return func(c *Client) {
+		c.expander = r
 	}
-	defer resp.Body.Close()
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
+// WithLocation sets the account time zone used by the date helpers, e.g. to decide
+// which days "this week" covers.
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		c.location = loc
 	}
+}
 
-	err = json.Unmarshal(data, timeEntries)
-	if err != nil {
-		return
+// WithWeekStart sets the day weeks start on for the date helpers.
+func WithWeekStart(day time.Weekday) ClientOption {
+	return func(c *Client) {
+		c.weekStart = day
 	}
-
-	return
 }
 
-// GetUsers returns all users - manual pagination per opts paramater
-// URL https://github.com/10Kft/10kft-api/blob/master/sections/users.md#endpoint-apiv1users
-func (c *Client) GetUsers(opts map[string]string) (users *Users, resp *http.Response, err error) {
-	users = &Users{Paging: &Paging{}}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/users?"+query, http.MethodGet, map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// WithUseNumber makes the client decode numbers in untyped fields, such as
+// EmployeeNumber and custom field values, and in the *Into variants as json.Number
+// instead of float64. Large IDs and counters then keep their exact value, including
+// when records are encoded again.
+func WithUseNumber() ClientOption {
+	return func(c *Client) {
+		c.useNumber = true
 	}
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// NewClient takes credentials and returns client to perform API operations on
+func NewClient(token, env string, opts ...ClientOption) (*Client, error) {
+	c := &Client{token: token, env: env, PageRetries: defaultPageRetries, weekStart: time.Monday, requeueBackoff: defaultRequeueBackoff}
+	for _, opt := range opts {
+		opt(c)
 	}
-	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
+	if err := validEnv(c.env); err != nil {
+		return &Client{}, err
 	}
 
-	err = json.Unmarshal(data, users)
-	if err != nil {
-		return
+	if err := c.useVersion(); err != nil {
+		return &Client{}, err
 	}
 
-	return
+	return c, nil
 }
 
-// GetUser returns a user based on a user object's ID
-func (c *Client) GetUser(u *User, opts map[string]string) (resp *http.Response, err error) {
-	query := queryfy(opts)
-	url := c.env + "/users/" + strconv.Itoa(u.ID) + "?" + query
-	method, headers := http.MethodGet, map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// NewClientWithCheck returns a client whose token has been verified with a lightweight
+// authenticated call. Unless WithEnv is passed the environment is detected by trying
+// Production, ResourceManagement, ResourceManagementEU and then Staging, skipping
+// those that are unreachable, use Env to find out which one was picked.
+// ErrInvalidToken is returned when the token is not accepted.
+func NewClientWithCheck(ctx context.Context, token string, opts ...ClientOption) (*Client, error) {
+	c := &Client{token: token, PageRetries: defaultPageRetries, weekStart: time.Monday, requeueBackoff: defaultRequeueBackoff}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	envs := environments
+	if c.env != "" {
+		if err := validEnv(c.env); err != nil {
+			return &Client{}, err
+		}
+		envs = []string{c.env}
 	}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+	var err error
+	for _, env := range envs {
+		c.env = env
+		if err = c.useVersion(); err != nil {
+			return &Client{}, err
+		}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+		var resp *http.Response
+		resp, err = c.check(ctx)
+		if err == nil {
+			return c, nil
+		}
 
-	err = json.Unmarshal(data, u)
-	if err != nil {
-		return
+		if resp != nil && resp.StatusCode != http.StatusUnauthorized {
+			return &Client{}, err
+		}
+		if resp != nil {
+			err = ErrInvalidToken
+		}
 	}
 
-	return
+	return &Client{}, err
 }
 
-// GetAllUsers returns all users - automatically paginates and returns the accumulated collection.
-// resp and err correspond to the latest one in the loop.
-// URL https://github.com/10Kft/10kft-api/blob/master/sections/users.md#endpoint-apiv1users
-func (c *Client) GetAllUsers(opts map[string]string) (users *Users, resp *http.Response, err error) {
-	users = &Users{Paging: &Paging{}}
-	opts["per_page"] = "201"
-	users, resp, err = c.GetUsers(opts)
-	if err != nil {
-		return
+// validEnv returns an error unless env is one of the environments.
+func validEnv(env string) error {
+	for _, e := range environments {
+		if env == e {
+			return nil
+		}
 	}
 
-	for loop := users.Paging.HasNext(); loop == true; loop = users.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(users.Paging.GetNextPage())
-		newUsers, newResp, newErr := c.GetUsers(opts)
-		resp = newResp
-		if err != nil {
-			err = newErr
-			break
-		}
+	return fmt.Errorf("env must be one of %v", strings.Join(environments, ", "))
+}
 
-		users.Paging = newUsers.Paging
-		users.Data = append(users.Data, newUsers.Data...)
-	}
+// RequestID returns the request ID 10000ft assigned to the call resp belongs to, or ""
+// if there is none. Quote it when opening tickets with 10000ft support. Errors for non
+// OK responses include it as well.
+func RequestID(resp *http.Response) string {
+	return utils.RequestID(resp)
+}
 
-	return
+// Env returns the URL the client talks to, the environment with the path of its
+// APIVersion.
+func (c *Client) Env() string {
+	return c.env
 }
 
-// CreateUser abstraction to POST /users
-func (c *Client) CreateUser(u *User) (resp *http.Response, err error) {
-	url, method, headers := c.env+"/users", http.MethodPost, map[string]string{"auth": c.token}
+// Auth sets token on the headers of a request, see HeaderAuth and BearerAuth.
+type Auth func(token string, headers map[string]string)
 
-	body, err := json.Marshal(u.baseUser)
-	if err != nil {
-		return
+// HeaderAuth sends the token as is in header name, 10000ft reads it from "auth".
+func HeaderAuth(name string) Auth {
+	return func(token string, headers map[string]string) {
+		headers[name] = token
 	}
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-	if err != nil {
-		return
+// BearerAuth sends the token as an "Authorization: Bearer" header, as expected by
+// API gateways and proxies enforcing OAuth style credentials.
+func BearerAuth() Auth {
+	return func(token string, headers map[string]string) {
+		headers["Authorization"] = "Bearer " + token
 	}
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// WithAuth sets how the token is sent, HeaderAuth("auth") by default.
+func WithAuth(auth Auth) ClientOption {
+	return func(c *Client) {
+		c.auth = auth
 	}
-	defer resp.Body.Close()
+}
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+// WithAuthHeader sets the header the token is sent in, it is short for
+// WithAuth(HeaderAuth(name)).
+func WithAuthHeader(name string) ClientOption {
+	return WithAuth(HeaderAuth(name))
+}
 
-	err = json.Unmarshal(b, u)
-	if err != nil {
-		return
+// headers returns the headers authenticating a request.
+func (c *Client) headers() map[string]string {
+	auth := c.auth
+	if auth == nil {
+		auth = HeaderAuth(defaultAuthHeader)
 	}
 
-	return
-}
+	headers := map[string]string{}
+	auth(c.token, headers)
 
-// DeleteUser archives user by updating it with archived set to true
-func (c *Client) DeleteUser(u *User) (*http.Response, error) {
-	u.Archived = true
-	return c.UpdateUser(u)
+	return headers
 }
 
-// UpdateUser abstraction to PUT /users/<id>
-func (c *Client) UpdateUser(u *User) (resp *http.Response, err error) {
-	url, method, headers := c.env+"/users/"+strconv.Itoa(u.ID), http.MethodPut, map[string]string{"auth": c.token}
-
-	body, err := json.Marshal(u.baseUser)
-	if err != nil {
-		return
-	}
+// check performs the smallest authenticated request available, without retries so
+// that a rejected token fails fast.
+func (c *Client) check(ctx context.Context) (resp *http.Response, err error) {
+	url, method, headers := c.env+"/roles?per_page=1", http.MethodGet, c.headers()
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
+	fetcher, err := utils.NewFetchOpts(url, method, "", headers, 0)
 	if err != nil {
 		return
 	}
+	fetcher.Context = ctx
+	fetcher.Client = c.httpClient
 
 	resp, err = fetcher.Fetch()
 	if err != nil {
+		err = c.apiError(method, url, resp, err)
 		return
 	}
-	defer resp.Body.Close()
-
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+	resp.Body.Close()
 
-	err = json.Unmarshal(b, u)
 	return
 }
 
-// CreateProject abstraction to POST /projects
-func (c *Client) CreateProject(p *Project) (resp *http.Response, err error) {
-	url, method, headers := c.env+"/projects", http.MethodPost, map[string]string{"auth": c.token}
-	body, err := json.Marshal(p.baseProject)
-	if err != nil {
-		return
-	}
+// retryPage calls fetch again when it fails with a 5xx status, backing off between
+// attempts, so that one bad page does not abort a long pagination loop.
+func (c *Client) retryPage(ctx context.Context, fetch func() (*http.Response, error)) (resp *http.Response, err error) {
+	backoff := pageRetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err = fetch()
+		if err == nil || resp == nil || resp.StatusCode < 500 || attempt >= c.PageRetries {
+			return
+		}
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-	if err != nil {
-		return
+		if err = utils.Sleep(ctx, backoff); err != nil {
+			return
+		}
+		backoff *= 2
 	}
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// queryfy encodes opts as a query string, escaping the values and sorting by key.
+func queryfy(opts map[string]string) string {
+	query := url.Values{}
+	for k, val := range opts {
+		query.Set(k, val)
 	}
-	defer resp.Body.Close()
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+	return query.Encode()
+}
 
-	err = json.Unmarshal(b, p)
-	if err != nil {
-		return
-	}
+// GetAllProjects returns all projects - automatically paginates and returns accumulated projects.
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllProjects(opts map[string]string) (*Projects, *http.Response, error) {
+	return c.GetAllProjectsCtx(context.Background(), opts)
+}
 
-	return
+// GetAllProjectsCtx is GetAllProjects honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllProjectsCtx(ctx context.Context, opts map[string]string) (*Projects, *http.Response, error) {
+	return projectsEndpoint.ListAll(ctx, c, opts)
 }
 
-// DeleteProject calls UpdateProject with archive set to true
-func (c *Client) DeleteProject(p *Project) (*http.Response, error) {
-	p.baseProject = &baseProject{Archived: true}
+// GetProjects returns all projects with default pagination
+func (c *Client) GetProjects(opts map[string]string) (*Projects, *http.Response, error) {
+	return c.GetProjectsCtx(context.Background(), opts)
+}
 
-	return c.UpdateProject(p)
+// GetProjectsCtx is GetProjects honouring ctx.
+func (c *Client) GetProjectsCtx(ctx context.Context, opts map[string]string) (*Projects, *http.Response, error) {
+	return projectsEndpoint.List(ctx, c, opts)
 }
 
-// UpdateProject abstraction to PUT /projects/<id>
-func (c *Client) UpdateProject(p *Project) (resp *http.Response, err error) {
-	url := c.env + "/projects/" + strconv.Itoa(p.ID)
-	method, headers := http.MethodPut, map[string]string{"auth": c.token}
+// CountProjects returns the number of projects matching opts without fetching them all.
+func (c *Client) CountProjects(opts map[string]string) (int, *http.Response, error) {
+	return c.CountProjectsCtx(context.Background(), opts)
+}
 
-	body, err := json.Marshal(p.baseProject)
-	if err != nil {
-		return
-	}
+// CountProjectsCtx is CountProjects honouring ctx.
+func (c *Client) CountProjectsCtx(ctx context.Context, opts map[string]string) (int, *http.Response, error) {
+	return projectsEndpoint.Count(ctx, c, opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetProjectsInto is GetProjects decoding the response into out instead of *Projects,
+// so that huge exports can use slim structs holding only the fields they need.
+// out is typically a pointer to a struct with Data and Paging fields.
+func (c *Client) GetProjectsInto(opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.GetProjectsIntoCtx(context.Background(), opts, out)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// GetProjectsIntoCtx is GetProjectsInto honouring ctx.
+func (c *Client) GetProjectsIntoCtx(ctx context.Context, opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, c.env+"/projects?"+queryfy(opts), nil, out)
+}
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+// GetUsersInto is GetUsers decoding the response into out, see GetProjectsInto.
+func (c *Client) GetUsersInto(opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.GetUsersIntoCtx(context.Background(), opts, out)
+}
 
-	err = json.Unmarshal(b, p)
+// GetUsersIntoCtx is GetUsersInto honouring ctx.
+func (c *Client) GetUsersIntoCtx(ctx context.Context, opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, c.env+"/users?"+queryfy(opts), nil, out)
+}
 
-	return
+// GetTimeEntriesInto is GetTimeEntries decoding the response into out, see GetProjectsInto.
+func (c *Client) GetTimeEntriesInto(opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.GetTimeEntriesIntoCtx(context.Background(), opts, out)
 }
 
-// GetAllUserAssignments - paginates through all assinments
-func (c *Client) GetAllUserAssignments(u *User, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
-	opts["per_page"] = "250"
-	assignments, resp, err = c.GetUserAssignments(u, opts)
-	if err != nil {
-		return
-	}
+// GetTimeEntriesIntoCtx is GetTimeEntriesInto honouring ctx.
+func (c *Client) GetTimeEntriesIntoCtx(ctx context.Context, opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, c.env+"/time_entries?"+queryfy(opts), nil, out)
+}
 
-	for loop := assignments.Paging.HasNext(); loop == true; loop = assignments.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(assignments.Paging.GetNextPage())
-		newAssignments, newResp, newErr := c.GetUserAssignments(u, opts)
-		resp = newResp
-		if err != nil {
-			err = newErr
-			break
-		}
+// GetUserAssignmentsInto is GetUserAssignments decoding the response into out, see GetProjectsInto.
+func (c *Client) GetUserAssignmentsInto(uID UserID, opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.GetUserAssignmentsIntoCtx(context.Background(), uID, opts, out)
+}
 
-		assignments.Paging = newAssignments.Paging
-		assignments.Data = append(assignments.Data, newAssignments.Data...)
-	}
+// GetUserAssignmentsIntoCtx is GetUserAssignmentsInto honouring ctx.
+func (c *Client) GetUserAssignmentsIntoCtx(ctx context.Context, uID UserID, opts map[string]string, out interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, c.env+"/users/"+strconv.Itoa(int(uID))+"/assignments?"+queryfy(opts), nil, out)
+}
 
-	return
+// GetTimeEntries returns all time entries with default pagination
+func (c *Client) GetTimeEntries(opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return c.GetTimeEntriesCtx(context.Background(), opts)
 }
 
-// GetUserAssignments retrieves all assignments for a user
-// https://github.com/10Kft/10kft-api/blob/master/sections/assignments.md#endpoint-apiv1usersuser_idassignments
-func (c *Client) GetUserAssignments(u *User, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
-	assignments = &Assignments{}
-	query := queryfy(opts)
-	url := c.env + "/users/" + strconv.Itoa(u.ID) + "/assignments?" + query
-	method := http.MethodGet
-	headers := map[string]string{"auth": c.token}
+// GetTimeEntriesCtx is GetTimeEntries honouring ctx.
+func (c *Client) GetTimeEntriesCtx(ctx context.Context, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return timeEntriesEndpoint.List(ctx, c, opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// CountUsers returns the number of users matching opts without fetching them all.
+func (c *Client) CountUsers(opts map[string]string) (int, *http.Response, error) {
+	return c.CountUsersCtx(context.Background(), opts)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// CountUsersCtx is CountUsers honouring ctx.
+func (c *Client) CountUsersCtx(ctx context.Context, opts map[string]string) (int, *http.Response, error) {
+	return usersEndpoint.Count(ctx, c, opts)
+}
+
+// GetUsers returns all users - manual pagination per opts paramater
+// URL https://github.com/10Kft/10kft-api/blob/master/sections/users.md#endpoint-apiv1users
+func (c *Client) GetUsers(opts map[string]string) (users *Users, resp *http.Response, err error) {
+	return c.GetUsersCtx(context.Background(), opts)
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
+// GetUsersCtx is GetUsers honouring ctx.
+func (c *Client) GetUsersCtx(ctx context.Context, opts map[string]string) (users *Users, resp *http.Response, err error) {
+	users, resp, err = usersEndpoint.List(ctx, c, opts)
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(data, assignments)
+	err = c.enrich(ctx, users.Data...)
 
 	return
 }
 
-// GetProjectAssignments retrieves all assignments for a project
-func (c *Client) GetProjectAssignments(p *Project, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
-	assignments = &Assignments{}
-	query := queryfy(opts)
-	url := c.env + "/projects/" + strconv.Itoa(p.ID) + "/assignments?" + query
-	method := http.MethodGet
-	headers := map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetUser returns a user based on a user object's ID
+func (c *Client) GetUser(u *User, opts map[string]string) (resp *http.Response, err error) {
+	return c.GetUserCtx(context.Background(), u, opts)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// GetUserCtx is GetUser honouring ctx.
+func (c *Client) GetUserCtx(ctx context.Context, u *User, opts map[string]string) (resp *http.Response, err error) {
+	url := c.env + "/users/" + strconv.Itoa(int(u.ID)) + "?" + queryfy(opts)
 
-	data, err := ioutil.ReadAll(resp.Body)
+	resp, err = c.do(ctx, http.MethodGet, url, nil, u)
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(data, assignments)
+	err = c.enrich(ctx, u)
 
 	return
 }
 
-// CreateUserAssignment abstraction to POST /users/<id>/assignments
-func (c *Client) CreateUserAssignment(a *Assignment) (resp *http.Response, err error) {
-	url := c.env + "/users/" + strconv.Itoa(a.UserID) + "/assignments"
-	method, headers := http.MethodPost, map[string]string{"auth": c.token}
+// GetUserByID abstraction to GET /users/<id>
+func (c *Client) GetUserByID(ID UserID, opts map[string]string) (*User, *http.Response, error) {
+	return c.GetUserByIDCtx(context.Background(), ID, opts)
+}
 
-	body, err := json.Marshal(a.baseAssignment)
+// GetUserByIDCtx is GetUserByID honouring ctx.
+func (c *Client) GetUserByIDCtx(ctx context.Context, ID UserID, opts map[string]string) (u *User, resp *http.Response, err error) {
+	u, resp, err = usersEndpoint.Get(ctx, c, int(ID), opts)
 	if err != nil {
 		return
 	}
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+	err = c.enrich(ctx, u)
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+	return
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+// FindUserByEmail returns the user with the given email, compared case insensitively,
+// or ErrUserNotFound. Users are listed with the email filter, pages being fetched until
+// the user turns up in case the API ignores it.
+func (c *Client) FindUserByEmail(email string) (*User, *http.Response, error) {
+	return c.FindUserByEmailCtx(context.Background(), email)
+}
+
+// FindUserByEmailCtx is FindUserByEmail honouring ctx.
+func (c *Client) FindUserByEmailCtx(ctx context.Context, email string) (u *User, resp *http.Response, err error) {
+	resp, err = usersEndpoint.eachPage(ctx, c, map[string]string{"email": email}, nil, func(pg *page[*User]) error {
+		for _, user := range pg.Data {
+			if strings.EqualFold(user.Email, email) {
+				u = user
+				return errFound
+			}
+		}
+		return nil
+	})
+	if err == errFound {
+		err = c.enrich(ctx, u)
 		return
 	}
-
-	err = json.Unmarshal(bytes, a)
+	if err == nil {
+		err = ErrUserNotFound
+	}
 
 	return
 }
 
-// GetProjectPhases abstraction to GET /projects/<id>/phases
-func (c *Client) GetProjectPhases(p *Project, opts map[string]string) (phases *Phases, resp *http.Response, err error) {
-	phases = &Phases{}
-	query := queryfy(opts)
-	url := c.env + "/projects/" + strconv.Itoa(p.ID) + "/phases?" + query
-	method, headers := http.MethodGet, map[string]string{"auth": c.token}
+// errFound stops a pagination once the item looked for has been found.
+var errFound = errors.New("found")
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetAllUsers returns all users - automatically paginates and returns the accumulated collection.
+// resp and err correspond to the latest one in the loop.
+// URL https://github.com/10Kft/10kft-api/blob/master/sections/users.md#endpoint-apiv1users
+func (c *Client) GetAllUsers(opts map[string]string) (*Users, *http.Response, error) {
+	return c.GetAllUsersCtx(context.Background(), opts)
+}
 
-	resp, err = fetcher.Fetch()
+// GetAllUsersCtx is GetAllUsers honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllUsersCtx(ctx context.Context, opts map[string]string) (users *Users, resp *http.Response, err error) {
+	users, resp, err = usersEndpoint.ListAll(ctx, c, opts)
 	if err != nil {
 		return
 	}
-	defer resp.Body.Close()
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	err = c.enrich(ctx, users.Data...)
 
-	if err != nil {
-		return
-	}
+	return
+}
 
-	err = json.Unmarshal(bytes, phases)
-	if err != nil {
-		return
-	}
+// CreateUser abstraction to POST /users
+func (c *Client) CreateUser(u *User) (*http.Response, error) {
+	return c.CreateUserCtx(context.Background(), u)
+}
 
-	return
+// CreateUserCtx is CreateUser honouring ctx.
+func (c *Client) CreateUserCtx(ctx context.Context, u *User) (*http.Response, error) {
+	return usersEndpoint.Create(ctx, c, u.baseUser, u)
 }
 
-// GetProjectByID abstraction to GET /projects/<id>
-func (c *Client) GetProjectByID(ID int, opts map[string]string) (p *Project, resp *http.Response, err error) {
-	p = &Project{}
-	query := queryfy(opts)
-	url := c.env + "/projects/" + strconv.Itoa(ID) + "?" + query
-	method, headers := http.MethodGet, map[string]string{"auth": c.token}
+// DeleteUser archives user by updating it with archived set to true
+func (c *Client) DeleteUser(u *User) (*http.Response, error) {
+	return c.DeleteUserCtx(context.Background(), u)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// DeleteUserCtx is DeleteUser honouring ctx.
+func (c *Client) DeleteUserCtx(ctx context.Context, u *User) (*http.Response, error) {
+	u.Archived = true
+	return c.UpdateUserCtx(ctx, u)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// UpdateUser abstraction to PUT /users/<id>
+func (c *Client) UpdateUser(u *User) (*http.Response, error) {
+	return c.UpdateUserCtx(context.Background(), u)
+}
+
+// UpdateUserCtx is UpdateUser honouring ctx.
+func (c *Client) UpdateUserCtx(ctx context.Context, u *User) (*http.Response, error) {
+	return usersEndpoint.Update(ctx, c, int(u.ID), u.baseUser, u)
+}
+
+// CreateProject abstraction to POST /projects
+func (c *Client) CreateProject(p *Project) (*http.Response, error) {
+	return c.CreateProjectCtx(context.Background(), p)
+}
+
+// CreateProjectCtx is CreateProject honouring ctx.
+func (c *Client) CreateProjectCtx(ctx context.Context, p *Project) (*http.Response, error) {
+	return projectsEndpoint.Create(ctx, c, p.baseProject, p)
+}
+
+// DeleteProject calls UpdateProject with archive set to true
+func (c *Client) DeleteProject(p *Project) (*http.Response, error) {
+	return c.DeleteProjectCtx(context.Background(), p)
+}
+
+// DeleteProjectCtx is DeleteProject honouring ctx.
+func (c *Client) DeleteProjectCtx(ctx context.Context, p *Project) (*http.Response, error) {
+	p.baseProject = &baseProject{Archived: true}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	return c.UpdateProjectCtx(ctx, p)
+}
+
+// UpdateProject abstraction to PUT /projects/<id>
+func (c *Client) UpdateProject(p *Project) (*http.Response, error) {
+	return c.UpdateProjectCtx(context.Background(), p)
+}
+
+// UpdateProjectCtx is UpdateProject honouring ctx.
+func (c *Client) UpdateProjectCtx(ctx context.Context, p *Project) (*http.Response, error) {
+	return projectsEndpoint.Update(ctx, c, int(p.ID), p.baseProject, p)
+}
+
+// GetAllUserAssignments - paginates through all assinments
+func (c *Client) GetAllUserAssignments(u *User, opts map[string]string) (*Assignments, *http.Response, error) {
+	return c.GetAllUserAssignmentsCtx(context.Background(), u, opts)
+}
+
+// GetAllUserAssignmentsCtx is GetAllUserAssignments honouring ctx, see Endpoint.ListAll
+// for what is returned when it is canceled.
+func (c *Client) GetAllUserAssignmentsCtx(ctx context.Context, u *User, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	assignments, resp, err = userAssignmentsEndpoint.ListAll(ctx, c, opts, int(u.ID))
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(bytes, p)
+	err = c.expand(assignments)
+
 	return
 }
 
-// CreateProjectPhase abstraction to POST /projects/<id>/phases
-func (c *Client) CreateProjectPhase(pID int, ph *Phase) (resp *http.Response, err error) {
-	url := c.env + "/projects/" + strconv.Itoa(pID) + "/phases"
-	method, headers := http.MethodPost, map[string]string{"auth": c.token}
-	body, err := json.Marshal(ph.basePhase)
-	if err != nil {
-		return
-	}
+// GetUserAssignments retrieves all assignments for a user
+// https://github.com/10Kft/10kft-api/blob/master/sections/assignments.md#endpoint-apiv1usersuser_idassignments
+func (c *Client) GetUserAssignments(u *User, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	return c.GetUserAssignmentsCtx(context.Background(), u, opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
+// GetUserAssignmentsCtx is GetUserAssignments honouring ctx.
+func (c *Client) GetUserAssignmentsCtx(ctx context.Context, u *User, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	assignments, resp, err = userAssignmentsEndpoint.List(ctx, c, opts, int(u.ID))
 	if err != nil {
 		return
 	}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+	err = c.expand(assignments)
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	return
+}
+
+// GetProjectAssignments retrieves all assignments for a project
+func (c *Client) GetProjectAssignments(p *Project, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	return c.GetProjectAssignmentsCtx(context.Background(), p, opts)
+}
+
+// GetProjectAssignmentsCtx is GetProjectAssignments honouring ctx.
+func (c *Client) GetProjectAssignmentsCtx(ctx context.Context, p *Project, opts map[string]string) (assignments *Assignments, resp *http.Response, err error) {
+	assignments, resp, err = projectAssignmentsEndpoint.List(ctx, c, opts, int(p.ID))
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(bytes, ph)
+	err = c.expand(assignments)
 
 	return
 }
 
-// CreateUserTags abstraction to POST /useres/<id>/tags
-func (c *Client) CreateUserTags(u *User) (resp *http.Response, err error) {
-	url := c.env + "/users/" + strconv.Itoa(u.ID) + "/tags"
-	method := http.MethodPost
-	headers := map[string]string{"auth": c.token}
+// expand expands assignments when the client was created WithAssignmentExpansion.
+func (c *Client) expand(assignments *Assignments) error {
+	if c.expander == nil {
+		return nil
+	}
 
-	for _, t := range u.Tags.Data {
-		body, err := json.Marshal(t.baseTag)
-		if err != nil {
-			return resp, err
-		}
+	return c.expander.ExpandAssignments(assignments)
+}
 
-		fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-		if err != nil {
-			return resp, err
-		}
+// CreateUserAssignment abstraction to POST /users/<id>/assignments
+func (c *Client) CreateUserAssignment(a *Assignment) (*http.Response, error) {
+	return c.CreateUserAssignmentCtx(context.Background(), a)
+}
 
-		resp, err = fetcher.Fetch()
-		if err != nil {
-			return resp, err
-		}
-		defer resp.Body.Close()
+// CreateUserAssignmentCtx is CreateUserAssignment honouring ctx.
+func (c *Client) CreateUserAssignmentCtx(ctx context.Context, a *Assignment) (*http.Response, error) {
+	return userAssignmentsEndpoint.Create(ctx, c, a.baseAssignment, a, int(a.UserID))
+}
 
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return resp, err
-		}
+// BookLeave books userID off on leaveTypeID from from to to inclusive, leave types
+// being assignables. hoursPerDay of 0 or less books full days. The created assignment
+// is returned.
+func (c *Client) BookLeave(userID UserID, leaveTypeID LeaveTypeID, from, to time.Time, hoursPerDay float64) (*Assignment, *http.Response, error) {
+	return c.BookLeaveCtx(context.Background(), userID, leaveTypeID, from, to, hoursPerDay)
+}
 
-		err = json.Unmarshal(b, t)
-		if err != nil {
-			return resp, err
-		}
+// BookLeaveCtx is BookLeave honouring ctx.
+func (c *Client) BookLeaveCtx(ctx context.Context, userID UserID, leaveTypeID LeaveTypeID, from, to time.Time, hoursPerDay float64) (a *Assignment, resp *http.Response, err error) {
+	if hoursPerDay > 0 {
+		a = NewAssignmentHoursPerDay(userID, AssignableID(leaveTypeID), from, to, hoursPerDay)
+	} else {
+		a = NewAssignmentPercent(userID, AssignableID(leaveTypeID), from, to, 1)
 	}
 
+	resp, err = c.CreateUserAssignmentCtx(ctx, a)
+
 	return
 }
 
-// CreateProjectTags abstraction to POST /projects/<id>/tags for each project tag.
-func (c *Client) CreateProjectTags(p *Project) (resp *http.Response, err error) {
-	url := c.env + "/projects/" + strconv.Itoa(p.ID) + "/tags"
-	method := http.MethodPost
-	headers := map[string]string{"auth": c.token}
+// CreateUserAssignmentOnWorkingDays creates a as several assignments when it has an
+// hours per day allocation spanning holidays or blackouts, see Calendar.SplitAssignment.
+// The user's availabilities are only taken into account when a was expanded. Nothing is
+// created and ErrNoWorkingDay is returned when a only covers days off. On error the
+// assignments created so far are returned.
+func (c *Client) CreateUserAssignmentOnWorkingDays(cal *Calendar, a *Assignment) (created []*Assignment, resp *http.Response, err error) {
+	return c.CreateUserAssignmentOnWorkingDaysCtx(context.Background(), cal, a)
+}
 
-	for _, t := range p.Tags.Data {
-		body, err := json.Marshal(t.baseTag)
-		if err != nil {
-			return resp, err
-		}
+// CreateUserAssignmentOnWorkingDaysCtx is CreateUserAssignmentOnWorkingDays honouring ctx.
+func (c *Client) CreateUserAssignmentOnWorkingDaysCtx(ctx context.Context, cal *Calendar, a *Assignment) (created []*Assignment, resp *http.Response, err error) {
+	runs, err := cal.SplitAssignment(a, a.User)
+	if err != nil {
+		return
+	}
 
-		fetcher, err := utils.NewFetchOpts(url, method, string(body), headers, c.MaxRetries)
-		if err != nil {
-			return resp, err
+	for _, run := range runs {
+		if resp, err = c.CreateUserAssignmentCtx(ctx, run); err != nil {
+			return
 		}
+		created = append(created, run)
+	}
 
-		resp, err = fetcher.Fetch()
-		if err != nil {
-			return resp, err
-		}
-		defer resp.Body.Close()
+	return
+}
 
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return resp, err
-		}
+// UpdateUserAssignment abstraction to PUT /users/<id>/assignments/<id>. Only a is
+// changed when it is an occurrence of a repeating assignment, see
+// UpdateUserAssignmentRepetition.
+func (c *Client) UpdateUserAssignment(a *Assignment) (*http.Response, error) {
+	return c.UpdateUserAssignmentCtx(context.Background(), a)
+}
 
-		err = json.Unmarshal(b, t)
-		if err != nil {
-			return resp, err
-		}
-	}
+// UpdateUserAssignmentCtx is UpdateUserAssignment honouring ctx.
+func (c *Client) UpdateUserAssignmentCtx(ctx context.Context, a *Assignment) (*http.Response, error) {
+	return userAssignmentsEndpoint.Update(ctx, c, int(a.ID), a.baseAssignment, a, int(a.UserID))
+}
 
-	return
+// DeleteUserAssignment abstraction to DELETE /users/<id>/assignments/<id>. Only a is
+// deleted when it is an occurrence of a repeating assignment, see
+// DeleteUserAssignmentRepetition.
+func (c *Client) DeleteUserAssignment(a *Assignment) (*http.Response, error) {
+	return c.DeleteUserAssignmentCtx(context.Background(), a)
 }
 
-// GetLeaveTypes abstraction to GET /leave_types
-func (c *Client) GetLeaveTypes(opts map[string]string) (leaveTypes *LeaveTypes, resp *http.Response, err error) {
-	leaveTypes = &LeaveTypes{}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/leave_types?"+query, http.MethodGet, map[string]string{"auth": c.token}
+// DeleteUserAssignmentCtx is DeleteUserAssignment honouring ctx.
+func (c *Client) DeleteUserAssignmentCtx(ctx context.Context, a *Assignment) (*http.Response, error) {
+	return userAssignmentsEndpoint.Delete(ctx, c, int(a.ID), int(a.UserID))
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// UpdateUserAssignmentRepetition applies the assignable and allocation of a to every
+// occurrence of its repetition, each keeping its own dates. It behaves like
+// UpdateUserAssignment when a doesn't repeat. On error the occurrences updated so far
+// are returned.
+func (c *Client) UpdateUserAssignmentRepetition(a *Assignment) (updated []*Assignment, resp *http.Response, err error) {
+	return c.UpdateUserAssignmentRepetitionCtx(context.Background(), a)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// UpdateUserAssignmentRepetitionCtx is UpdateUserAssignmentRepetition honouring ctx.
+func (c *Client) UpdateUserAssignmentRepetitionCtx(ctx context.Context, a *Assignment) (updated []*Assignment, resp *http.Response, err error) {
+	if a.baseAssignment == nil {
+		return nil, nil, ErrNoAllocation
 	}
-	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
+	occurrences, resp, err := c.repetition(ctx, a)
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(data, leaveTypes)
-	if err != nil {
-		return
+	for _, o := range occurrences {
+		base := *a.baseAssignment
+		if o.baseAssignment != nil {
+			base.StartsAt, base.EndsAt = o.StartsAt, o.EndsAt
+		}
+		o.baseAssignment = &base
+
+		if resp, err = c.UpdateUserAssignmentCtx(ctx, o); err != nil {
+			return
+		}
+		updated = append(updated, o)
 	}
 
 	return
 }
 
-// GetAllLeaveTypes returns all leave types - automatically paginates and returns accumulated leave types.
-// resp and err correspond to the latest one in the loop.
-func (c *Client) GetAllLeaveTypes(opts map[string]string) (leaveTypes *LeaveTypes, resp *http.Response, err error) {
-	opts["per_page"] = "50"
-	leaveTypes, resp, err = c.GetLeaveTypes(opts)
+// DeleteUserAssignmentRepetition deletes every occurrence of the repetition a belongs
+// to. It behaves like DeleteUserAssignment when a doesn't repeat. On error the
+// occurrences deleted so far are returned.
+func (c *Client) DeleteUserAssignmentRepetition(a *Assignment) (deleted []*Assignment, resp *http.Response, err error) {
+	return c.DeleteUserAssignmentRepetitionCtx(context.Background(), a)
+}
+
+// DeleteUserAssignmentRepetitionCtx is DeleteUserAssignmentRepetition honouring ctx.
+func (c *Client) DeleteUserAssignmentRepetitionCtx(ctx context.Context, a *Assignment) (deleted []*Assignment, resp *http.Response, err error) {
+	occurrences, resp, err := c.repetition(ctx, a)
 	if err != nil {
 		return
 	}
 
-	for loop := leaveTypes.Paging.HasNext(); loop == true; loop = leaveTypes.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(leaveTypes.Paging.GetNextPage())
-		newLeaveTypes, newResp, newErr := c.GetLeaveTypes(opts)
-		resp = newResp
-		if err != nil {
-			err = newErr
-			break
+	for _, o := range occurrences {
+		if resp, err = c.DeleteUserAssignmentCtx(ctx, o); err != nil {
+			return
 		}
-
-		leaveTypes.Paging = newLeaveTypes.Paging
-		leaveTypes.Data = append(leaveTypes.Data, newLeaveTypes.Data...)
+		deleted = append(deleted, o)
 	}
 
 	return
 }
 
-// GetRoles returns all Role types for an account.
-func (c *Client) GetRoles(opts map[string]string) (roles *Roles, resp *http.Response, err error) {
-	roles = &Roles{}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/roles?"+query, http.MethodGet, map[string]string{"auth": c.token}
-
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// repetition returns the assignments of a's user sharing its RepetitionID, a alone when
+// it doesn't repeat. Occurrences are looked for repetitionYears around a.
+func (c *Client) repetition(ctx context.Context, a *Assignment) (occurrences []*Assignment, resp *http.Response, err error) {
+	if a.RepetitionID == 0 {
+		return []*Assignment{a}, nil, nil
 	}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+	day := time.Now()
+	if a.baseAssignment != nil {
+		if startsAt, err := time.ParseInLocation(DateFormat, a.StartsAt, c.Location()); err == nil {
+			day = startsAt
+		}
+	}
+	query := map[string]string{
+		"from": day.AddDate(-repetitionYears, 0, 0).Format(DateFormat),
+		"to":   day.AddDate(repetitionYears, 0, 0).Format(DateFormat),
 	}
-	defer resp.Body.Close()
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	assignments, resp, err := userAssignmentsEndpoint.ListAll(ctx, c, query, int(a.UserID))
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(bytes, roles)
+	for _, o := range assignments.Data {
+		if o.RepetitionID == a.RepetitionID {
+			occurrences = append(occurrences, o)
+		}
+	}
 
 	return
 }
 
-// GetAllRoles returns all role types - automatically paginates and returns accumulated roles
-// resp and err correspond to the latest one in the loop.
-func (c *Client) GetAllRoles(opts map[string]string) (roles *Roles, resp *http.Response, err error) {
-	opts["per_page"] = "50"
-	roles, resp, err = c.GetRoles(opts)
-	if err != nil {
-		return
-	}
-
-	for loop := roles.Paging.HasNext(); loop == true; loop = roles.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(roles.Paging.GetNextPage())
-		newRoles, newResp, newErr := c.GetRoles(opts)
-		resp = newResp
-		if err != nil {
-			err = newErr
-			break
-		}
+// GetProjectPhases abstraction to GET /projects/<id>/phases
+func (c *Client) GetProjectPhases(p *Project, opts map[string]string) (*Phases, *http.Response, error) {
+	return c.GetProjectPhasesCtx(context.Background(), p, opts)
+}
 
-		roles.Paging = newRoles.Paging
-		roles.Data = append(roles.Data, newRoles.Data...)
-	}
+// GetProjectPhasesCtx is GetProjectPhases honouring ctx.
+func (c *Client) GetProjectPhasesCtx(ctx context.Context, p *Project, opts map[string]string) (*Phases, *http.Response, error) {
+	return phasesEndpoint.List(ctx, c, opts, int(p.ID))
+}
 
-	return
+// GetProjectByID abstraction to GET /projects/<id>
+func (c *Client) GetProjectByID(ID ProjectID, opts map[string]string) (*Project, *http.Response, error) {
+	return c.GetProjectByIDCtx(context.Background(), ID, opts)
 }
 
-// GetProjectBillRates returns all bill rates for a project.
-func (c *Client) GetProjectBillRates(pID int, opts map[string]string) (billRates *BillRates, resp *http.Response, err error) {
-	billRates = &BillRates{}
-	query := queryfy(opts)
-	url := c.env + "/projects/" + strconv.Itoa(pID) + "/bill_rates?" + query
-	method, headers := http.MethodGet, map[string]string{"auth": c.token}
+// GetProjectByIDCtx is GetProjectByID honouring ctx.
+func (c *Client) GetProjectByIDCtx(ctx context.Context, ID ProjectID, opts map[string]string) (*Project, *http.Response, error) {
+	return projectsEndpoint.Get(ctx, c, int(ID), opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// RefreshSecureURL returns a secure URL of p that has not expired. When
+// SecureURLExpiration has passed p is refetched and its SecureURL and
+// SecureURLExpiration updated, otherwise no request is made and resp is nil.
+func (c *Client) RefreshSecureURL(p *Project) (url string, resp *http.Response, err error) {
+	return c.RefreshSecureURLCtx(context.Background(), p)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
+// RefreshSecureURLCtx is RefreshSecureURL honouring ctx.
+func (c *Client) RefreshSecureURLCtx(ctx context.Context, p *Project) (url string, resp *http.Response, err error) {
+	if p.SecureURL == "" || !secureURLExpired(p.SecureURLExpiration) {
+		return p.SecureURL, nil, nil
 	}
-	defer resp.Body.Close()
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	fresh, resp, err := c.GetProjectByIDCtx(ctx, p.ID, map[string]string{})
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(bytes, billRates)
+	p.SecureURL, p.SecureURLExpiration = fresh.SecureURL, fresh.SecureURLExpiration
+	url = p.SecureURL
 
 	return
 }
 
-// GetAllProjectBillRates returns all project bill rates - automatically paginates and returns accumulated response
-// resp and err correspond to the latest one in the loop.
-func (c *Client) GetAllProjectBillRates(pID int, opts map[string]string) (billRates *BillRates, resp *http.Response, err error) {
-	opts["per_page"] = "50"
-	billRates, resp, err = c.GetProjectBillRates(pID, opts)
-	if err != nil {
-		return
+// secureURLExpired reports whether a secureurl_expiration has passed, unparsable
+// expirations are treated as expired.
+func secureURLExpired(expiration string) bool {
+	if expiration == "" {
+		return false
 	}
 
-	for loop := billRates.Paging.HasNext(); loop == true; loop = billRates.Paging.HasNext() {
-		opts["page"] = strconv.Itoa(billRates.Paging.GetNextPage())
-		newBillRates, newResp, newErr := c.GetProjectBillRates(pID, opts)
-		resp = newResp
+	t, err := time.Parse(time.RFC3339, expiration)
+
+	return err != nil || !time.Now().Before(t)
+}
+
+// CreateProjectPhase abstraction to POST /projects/<id>/phases
+func (c *Client) CreateProjectPhase(pID ProjectID, ph *Phase) (*http.Response, error) {
+	return c.CreateProjectPhaseCtx(context.Background(), pID, ph)
+}
+
+// CreateProjectPhaseCtx is CreateProjectPhase honouring ctx.
+func (c *Client) CreateProjectPhaseCtx(ctx context.Context, pID ProjectID, ph *Phase) (*http.Response, error) {
+	return phasesEndpoint.Create(ctx, c, ph.basePhase, ph, int(pID))
+}
+
+// UpdateProjectPhase abstraction to PUT /projects/<id>/phases/<phase_id>, the project
+// being ph.ParentID.
+func (c *Client) UpdateProjectPhase(ph *Phase) (*http.Response, error) {
+	return c.UpdateProjectPhaseCtx(context.Background(), ph)
+}
+
+// UpdateProjectPhaseCtx is UpdateProjectPhase honouring ctx.
+func (c *Client) UpdateProjectPhaseCtx(ctx context.Context, ph *Phase) (*http.Response, error) {
+	return phasesEndpoint.Update(ctx, c, int(ph.ID), ph.basePhase, ph, int(ph.ParentID))
+}
+
+// DeleteProjectPhase archives the phase like DeleteProject does projects, only sending
+// archived so that the other fields of ph are left as they are.
+func (c *Client) DeleteProjectPhase(ph *Phase) (*http.Response, error) {
+	return c.DeleteProjectPhaseCtx(context.Background(), ph)
+}
+
+// DeleteProjectPhaseCtx is DeleteProjectPhase honouring ctx.
+func (c *Client) DeleteProjectPhaseCtx(ctx context.Context, ph *Phase) (*http.Response, error) {
+	archive := struct {
+		Archived bool `json:"archived"`
+	}{true}
+
+	return phasesEndpoint.Update(ctx, c, int(ph.ID), archive, ph, int(ph.ParentID))
+}
+
+// CreateUserTags abstraction to POST /useres/<id>/tags
+func (c *Client) CreateUserTags(u *User) (resp *http.Response, err error) {
+	return c.CreateUserTagsCtx(context.Background(), u)
+}
+
+// CreateUserTagsCtx is CreateUserTags honouring ctx.
+func (c *Client) CreateUserTagsCtx(ctx context.Context, u *User) (resp *http.Response, err error) {
+	for _, t := range u.Tags.Data {
+		resp, err = userTagsEndpoint.Create(ctx, c, t.baseTag, t, int(u.ID))
 		if err != nil {
-			err = newErr
-			break
+			return
 		}
-
-		billRates.Paging = newBillRates.Paging
-		billRates.Data = append(billRates.Data, newBillRates.Data...)
 	}
 
 	return
 }
 
-// GetProjectUsers returns a project's users /projects/<id>/users
-func (c *Client) GetProjectUsers(pID int, opts map[string]string) (users *Users, resp *http.Response, err error) {
-	users = &Users{}
-	query := queryfy(opts)
-	url := c.env + "/projects/" + strconv.Itoa(pID) + "/users?" + query
-	method, headers := http.MethodGet, map[string]string{"auth": c.token}
+// CreateProjectTags abstraction to POST /projects/<id>/tags for each project tag.
+func (c *Client) CreateProjectTags(p *Project) (resp *http.Response, err error) {
+	return c.CreateProjectTagsCtx(context.Background(), p)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
+// CreateProjectTagsCtx is CreateProjectTags honouring ctx.
+func (c *Client) CreateProjectTagsCtx(ctx context.Context, p *Project) (resp *http.Response, err error) {
+	for _, t := range p.Tags.Data {
+		resp, err = projectTagsEndpoint.Create(ctx, c, t.baseTag, t, int(p.ID))
+		if err != nil {
+			return
+		}
 	}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+	return
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+// GetLeaveTypes abstraction to GET /leave_types
+func (c *Client) GetLeaveTypes(opts map[string]string) (*LeaveTypes, *http.Response, error) {
+	return c.GetLeaveTypesCtx(context.Background(), opts)
+}
+
+// GetLeaveTypesCtx is GetLeaveTypes honouring ctx.
+func (c *Client) GetLeaveTypesCtx(ctx context.Context, opts map[string]string) (*LeaveTypes, *http.Response, error) {
+	return leaveTypesEndpoint.List(ctx, c, opts)
+}
 
-	err = json.Unmarshal(bytes, users)
+// GetAllLeaveTypes returns all leave types - automatically paginates and returns accumulated leave types.
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllLeaveTypes(opts map[string]string) (*LeaveTypes, *http.Response, error) {
+	return c.GetAllLeaveTypesCtx(context.Background(), opts)
+}
 
-	return
+// GetAllLeaveTypesCtx is GetAllLeaveTypes honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllLeaveTypesCtx(ctx context.Context, opts map[string]string) (*LeaveTypes, *http.Response, error) {
+	return leaveTypesEndpoint.ListAll(ctx, c, opts)
 }
 
-// GetApprovals returns all Approval types for an account.
-func (c *Client) GetApprovals(opts map[string]string) (approvals *Approvals, resp *http.Response, err error) {
-	approvals = &Approvals{}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/approvals?"+query, http.MethodGet, map[string]string{"auth": c.token}
+// GetRoles returns all Role types for an account.
+func (c *Client) GetRoles(opts map[string]string) (*Roles, *http.Response, error) {
+	return c.GetRolesCtx(context.Background(), opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetRolesCtx is GetRoles honouring ctx.
+func (c *Client) GetRolesCtx(ctx context.Context, opts map[string]string) (*Roles, *http.Response, error) {
+	return rolesEndpoint.List(ctx, c, opts)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// GetAllRoles returns all role types - automatically paginates and returns accumulated roles
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllRoles(opts map[string]string) (*Roles, *http.Response, error) {
+	return c.GetAllRolesCtx(context.Background(), opts)
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+// GetAllRolesCtx is GetAllRoles honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllRolesCtx(ctx context.Context, opts map[string]string) (*Roles, *http.Response, error) {
+	return rolesEndpoint.ListAll(ctx, c, opts)
+}
 
-	err = json.Unmarshal(bytes, approvals)
+// GetProjectBillRates returns all bill rates for a project.
+func (c *Client) GetProjectBillRates(pID ProjectID, opts map[string]string) (*BillRates, *http.Response, error) {
+	return c.GetProjectBillRatesCtx(context.Background(), pID, opts)
+}
 
-	return
+// GetProjectBillRatesCtx is GetProjectBillRates honouring ctx.
+func (c *Client) GetProjectBillRatesCtx(ctx context.Context, pID ProjectID, opts map[string]string) (*BillRates, *http.Response, error) {
+	return projectBillRatesEndpoint.List(ctx, c, opts, int(pID))
 }
 
-// GetHolidays returns all Holiday types for an account.
-func (c *Client) GetHolidays(opts map[string]string) (holidays *Holidays, resp *http.Response, err error) {
-	holidays = &Holidays{}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/holidays?"+query, http.MethodGet, map[string]string{"auth": c.token}
+// GetAllProjectBillRates returns all project bill rates - automatically paginates and returns accumulated response
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllProjectBillRates(pID ProjectID, opts map[string]string) (*BillRates, *http.Response, error) {
+	return c.GetAllProjectBillRatesCtx(context.Background(), pID, opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetAllProjectBillRatesCtx is GetAllProjectBillRates honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllProjectBillRatesCtx(ctx context.Context, pID ProjectID, opts map[string]string) (*BillRates, *http.Response, error) {
+	return projectBillRatesEndpoint.ListAll(ctx, c, opts, int(pID))
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// GetProjectUsers returns a project's users /projects/<id>/users
+func (c *Client) GetProjectUsers(pID ProjectID, opts map[string]string) (users *Users, resp *http.Response, err error) {
+	return c.GetProjectUsersCtx(context.Background(), pID, opts)
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+// GetProjectUsersCtx is GetProjectUsers honouring ctx.
+func (c *Client) GetProjectUsersCtx(ctx context.Context, pID ProjectID, opts map[string]string) (users *Users, resp *http.Response, err error) {
+	users, resp, err = projectUsersEndpoint.List(ctx, c, opts, int(pID))
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(bytes, holidays)
+	err = c.enrich(ctx, users.Data...)
 
 	return
 }
 
-// GetDisciplines returns all Discipline types for an account.
-func (c *Client) GetDisciplines(opts map[string]string) (disciplines *Disciplines, resp *http.Response, err error) {
-	disciplines = &Disciplines{}
-	query := queryfy(opts)
-	url, method, headers := c.env+"/disciplines?"+query, http.MethodGet, map[string]string{"auth": c.token}
+// GetApprovals returns all Approval types for an account.
+func (c *Client) GetApprovals(opts map[string]string) (*Approvals, *http.Response, error) {
+	return c.GetApprovalsCtx(context.Background(), opts)
+}
 
-	fetcher, err := utils.NewFetchOpts(url, method, "", headers, c.MaxRetries)
-	if err != nil {
-		return
-	}
+// GetApprovalsCtx is GetApprovals honouring ctx.
+func (c *Client) GetApprovalsCtx(ctx context.Context, opts map[string]string) (*Approvals, *http.Response, error) {
+	return approvalsEndpoint.List(ctx, c, opts)
+}
 
-	resp, err = fetcher.Fetch()
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
+// GetHolidays returns all Holiday types for an account.
+func (c *Client) GetHolidays(opts map[string]string) (*Holidays, *http.Response, error) {
+	return c.GetHolidaysCtx(context.Background(), opts)
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
+// GetHolidaysCtx is GetHolidays honouring ctx.
+func (c *Client) GetHolidaysCtx(ctx context.Context, opts map[string]string) (*Holidays, *http.Response, error) {
+	return holidaysEndpoint.List(ctx, c, opts)
+}
 
-	err = json.Unmarshal(bytes, disciplines)
+// GetAllHolidays returns all holidays - automatically paginates and returns accumulated holidays
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllHolidays(opts map[string]string) (*Holidays, *http.Response, error) {
+	return c.GetAllHolidaysCtx(context.Background(), opts)
+}
 
-	return
+// GetAllHolidaysCtx is GetAllHolidays honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllHolidaysCtx(ctx context.Context, opts map[string]string) (*Holidays, *http.Response, error) {
+	return holidaysEndpoint.ListAll(ctx, c, opts)
+}
+
+// GetDisciplines returns all Discipline types for an account.
+func (c *Client) GetDisciplines(opts map[string]string) (*Disciplines, *http.Response, error) {
+	return c.GetDisciplinesCtx(context.Background(), opts)
+}
+
+// GetDisciplinesCtx is GetDisciplines honouring ctx.
+func (c *Client) GetDisciplinesCtx(ctx context.Context, opts map[string]string) (*Disciplines, *http.Response, error) {
+	return disciplinesEndpoint.List(ctx, c, opts)
+}
+
+// GetAllDisciplines returns all discipline types - automatically paginates and returns accumulated disciplines
+// resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllDisciplines(opts map[string]string) (*Disciplines, *http.Response, error) {
+	return c.GetAllDisciplinesCtx(context.Background(), opts)
+}
+
+// GetAllDisciplinesCtx is GetAllDisciplines honouring ctx, see Endpoint.ListAll for what is returned
+// when it is canceled.
+func (c *Client) GetAllDisciplinesCtx(ctx context.Context, opts map[string]string) (*Disciplines, *http.Response, error) {
+	return disciplinesEndpoint.ListAll(ctx, c, opts)
 }
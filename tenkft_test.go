@@ -1,14 +1,40 @@
 package tenkft
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/workco/go-tenkft/utils"
 )
 
 var c, _ = NewClient(os.Getenv("TEN_K_DEV"), Staging)
 var projects = &Projects{}
 
+// newTestClient returns a client talking to a fake API served by routes, keyed by path.
+func newTestClient(t *testing.T, routes map[string]string) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &Client{token: "test", env: srv.URL, weekStart: time.Monday}
+}
+
 func TestConstructors(t *testing.T) {
 	projects = NewProjects()
 	if projects.Paging == nil {
@@ -29,6 +55,24 @@ func TestConstructors(t *testing.T) {
 	if user.baseUser == nil {
 		t.Error("expected baseUser to be non nil")
 	}
+	if user.Tags.Paging == nil || project.Assignments.Data == nil {
+		t.Error("expected nested collections to be non nil")
+	}
+
+	collections := []interface{}{
+		NewAssignments(), NewPhases(), NewTags(), NewAvailabilities(), NewLeaveTypes(), NewRoles(),
+		NewBillRates(), NewTimeEntries(), NewHolidays(), NewApprovals(), NewDisciplines(), NewBudgetItems(), NewExpenseItems(),
+	}
+	for _, collection := range collections {
+		v := reflect.ValueOf(collection).Elem()
+		if v.FieldByName("Data").IsNil() || v.FieldByName("Paging").IsNil() {
+			t.Errorf("expected %T to have non nil data and paging", collection)
+		}
+	}
+
+	if NewPhase().basePhase == nil || NewTag("go").Value != "go" {
+		t.Error("expected phase and tag bases to be non nil")
+	}
 }
 
 func TestGetProjects(t *testing.T) {
@@ -73,3 +117,442 @@ func TestGetAllUsers(t *testing.T) {
 		fmt.Println("all projects returned an empty slice")
 	}
 }
+
+func TestRetryPage(t *testing.T) {
+	backoff := pageRetryBackoff
+	t.Cleanup(func() { pageRetryBackoff = backoff })
+	pageRetryBackoff = 0
+
+	client := &Client{PageRetries: 2}
+
+	calls := 0
+	resp, err := client.retryPage(context.Background(), func() (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return &http.Response{StatusCode: http.StatusBadGateway}, errors.New("bad gateway")
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the page to succeed after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %v", calls)
+	}
+
+	calls = 0
+	_, err = client.retryPage(context.Background(), func() (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusNotFound}, errors.New("not found")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected a 4xx not to be retried, got %v calls", calls)
+	}
+}
+
+func TestGetProjectsInto(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/projects": `{"data": [{"id": 1, "name": "Engine", "description": "not needed"}], "paging": {"page": 1}}`,
+	})
+
+	var slim struct {
+		Data []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+		Paging *Paging `json:"paging"`
+	}
+	_, err := client.GetProjectsInto(map[string]string{}, &slim)
+	if err != nil {
+		t.Fatal("could not get projects", err)
+	}
+
+	if len(slim.Data) != 1 || slim.Data[0].Name != "Engine" || slim.Paging.Page != 1 {
+		t.Errorf("unexpected decoded projects %+v", slim)
+	}
+}
+
+func TestRequestIDInErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc-123")
+		http.Error(w, `{"message": "nope"}`, http.StatusUnprocessableEntity)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	_, resp, err := client.GetProjects(map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "abc-123") {
+		t.Errorf("expected the error to carry the request id, got %v", err)
+	}
+	if RequestID(resp) != "abc-123" {
+		t.Errorf("expected request id abc-123, got %q", RequestID(resp))
+	}
+}
+
+func TestRefreshSecureURLNotExpired(t *testing.T) {
+	client := newTestClient(t, map[string]string{})
+	expiration := time.Now().Add(time.Hour).Format(time.RFC3339)
+	p := &Project{ID: 1, SecureURL: "https://example.com/secure", SecureURLExpiration: expiration}
+
+	url, resp, err := client.RefreshSecureURL(p)
+	if err != nil || resp != nil || url != p.SecureURL {
+		t.Errorf("expected the current secure url without a request, got %v, %v", url, err)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/projects": `{"data": [{"id": 1, "name": "Engine", "scheduled_dollars": 1000, "settings": {"bill_rate": 150}}], "paging": {}}`,
+	})
+	WithRedaction(CompensationFields...)(client)
+
+	projects, _, err := client.GetProjects(map[string]string{})
+	if err != nil {
+		t.Fatal("could not get projects", err)
+	}
+
+	p := projects.Data[0]
+	if p.Name != "Engine" || p.ScheduledDollars != 0 {
+		t.Errorf("expected dollars to be redacted and the rest kept, got %+v", p)
+	}
+	var settings map[string]interface{}
+	if err := p.Settings.Decode(&settings); err != nil || len(settings) != 0 {
+		t.Errorf("expected nested bill rates to be redacted, got %v", settings)
+	}
+}
+
+func TestUseNumber(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/users": `{"data": [{"id": 1, "employee_number": 12345678901234567890}], "paging": {}}`,
+	})
+	WithUseNumber()(client)
+
+	users, _, err := client.GetUsers(map[string]string{})
+	if err != nil {
+		t.Fatal("could not get users", err)
+	}
+
+	n, ok := users.Data[0].EmployeeNumber.(json.Number)
+	if !ok || n.String() != "12345678901234567890" {
+		t.Errorf("expected the exact employee number, got %v", users.Data[0].EmployeeNumber)
+	}
+}
+
+func TestRetryStats(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/projects/42": `{"id": 42, "name": "Engine"}`,
+	})
+
+	var endpoint string
+	var hooked utils.RetryStats
+	WithRetryHook(func(method, e string, stats utils.RetryStats) {
+		endpoint, hooked = method+" "+e, stats
+	})(client)
+
+	_, resp, err := client.GetProjectByID(42, map[string]string{})
+	if err != nil {
+		t.Fatal("could not get project", err)
+	}
+
+	stats := Retries(resp)
+	if stats.Attempts != 1 || stats.StatusCode != http.StatusOK || stats.Backoff != 0 {
+		t.Errorf("expected a single successful attempt, got %+v", stats)
+	}
+	if hooked != stats || endpoint != "GET /projects/%d" {
+		t.Errorf("expected the hook to get %+v for GET /projects/%%d, got %+v for %v", stats, hooked, endpoint)
+	}
+}
+
+func TestAuthHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "test" || r.Header.Get("auth") != "" {
+			http.Error(w, `{"message": "unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"data": [], "paging": {}}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("test", ResourceManagement, WithAuthHeader("X-Api-Key"))
+	if err != nil || client.Env() != ResourceManagement {
+		t.Fatalf("expected resource management to be accepted, got %v", err)
+	}
+	client.env = srv.URL
+
+	if _, _, err := client.GetProjects(map[string]string{}); err != nil {
+		t.Errorf("expected the token in X-Api-Key, got %v", err)
+	}
+
+	headers := (&Client{token: "test", auth: BearerAuth()}).headers()
+	if len(headers) != 1 || headers["Authorization"] != "Bearer test" {
+		t.Errorf("expected a bearer token, got %v", headers)
+	}
+}
+
+func TestCtxVariants(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"/projects/1": `{"id": 1, "name": "Engine"}`,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := client.GetProjectByIDCtx(ctx, 1, map[string]string{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled request, got %v", err)
+	}
+	if _, err := client.UpdateProjectCtx(ctx, &Project{ID: 1, baseProject: &baseProject{}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled update, got %v", err)
+	}
+
+	p, _, err := client.GetProjectByIDCtx(context.Background(), 1, map[string]string{})
+	if err != nil || p.Name != "Engine" {
+		t.Errorf("expected the project, got %+v, %v", p, err)
+	}
+}
+
+func TestAssignmentConstructors(t *testing.T) {
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 4)
+
+	a := NewAssignmentHoursPerDay(7, 42, from, to, 6)
+	b, err := json.Marshal(a.baseAssignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"allocation_mode":"hours_per_day","assignable_id":42,"ends_at":"2024-03-08","hours_per_day":6,"starts_at":"2024-03-04"}`
+	if string(b) != expected || a.UserID != 7 {
+		t.Errorf("expected %v for user 7, got %s for user %v", expected, b, a.UserID)
+	}
+
+	if a := NewAssignmentPercent(7, 42, from, to, 0.5); a.AllocationMode != AllocationPercent || a.Percent != 0.5 || a.HoursPerDay != 0 || a.FixedHours != 0 {
+		t.Errorf("expected a percent only allocation, got %+v", a.baseAssignment)
+	}
+	if a := NewAssignmentFixedHours(7, 42, from, to, 20); a.AllocationMode != AllocationFixed || a.FixedHours != 20 || a.Percent != 0 {
+		t.Errorf("expected a fixed hours only allocation, got %+v", a.baseAssignment)
+	}
+}
+
+func TestAssignmentRepetitions(t *testing.T) {
+	var calls []string
+	var hours []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			// Like the API, only list the coming weeks unless asked otherwise.
+			from, to := time.Now().Format(DateFormat), time.Now().AddDate(0, 0, 28).Format(DateFormat)
+			if q := r.URL.Query(); q.Get("from") != "" {
+				from, to = q.Get("from"), q.Get("to")
+			}
+			if from > "2024-03-04" || to < "2024-03-11" {
+				fmt.Fprint(w, `{"data": [], "paging": {}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": [
+				{"id": 1, "user_id": 7, "repetition_id": 3, "starts_at": "2024-03-04", "ends_at": "2024-03-04"},
+				{"id": 2, "user_id": 7, "starts_at": "2024-03-05", "ends_at": "2024-03-05"},
+				{"id": 4, "user_id": 7, "repetition_id": 3, "starts_at": "2024-03-11", "ends_at": "2024-03-11"}
+			], "paging": {}}`)
+		case http.MethodPut:
+			var a Assignment
+			json.NewDecoder(r.Body).Decode(&a)
+			hours = append(hours, a.HoursPerDay)
+			if a.StartsAt != "2024-03-04" && a.StartsAt != "2024-03-11" {
+				t.Errorf("expected occurrences to keep their dates, got %v", a.StartsAt)
+			}
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	// The occurrences are outside the default window around a.
+	a := NewAssignmentHoursPerDay(7, 42, time.Now(), time.Now(), 2)
+	a.ID, a.RepetitionID = 1, 3
+	updated, _, err := client.UpdateUserAssignmentRepetition(a)
+	if err != nil {
+		t.Fatal("could not update repetition", err)
+	}
+	if len(updated) != 2 || len(hours) != 2 || hours[0] != 2 || hours[1] != 2 {
+		t.Errorf("expected both occurrences to be updated, got %v", hours)
+	}
+
+	calls = nil
+	deleted, _, err := client.DeleteUserAssignmentRepetition(a)
+	if err != nil {
+		t.Fatal("could not delete repetition", err)
+	}
+	expected := []string{"GET /users/7/assignments", "DELETE /users/7/assignments/1", "DELETE /users/7/assignments/4"}
+	if len(deleted) != 2 || !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	calls = nil
+	if _, _, err := client.DeleteUserAssignmentRepetition(&Assignment{ID: 2, UserID: 7}); err != nil || len(calls) != 1 {
+		t.Errorf("expected a single delete for a non repeating assignment, got %v, %v", calls, err)
+	}
+
+	calls = nil
+	if _, _, err := client.UpdateUserAssignmentRepetition(&Assignment{ID: 1, UserID: 7, RepetitionID: 3}); err != ErrNoAllocation || len(calls) != 0 {
+		t.Errorf("expected ErrNoAllocation without a call, got %v, %v", calls, err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithTransport(t *testing.T) {
+	var paths []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"data": [{"id": 1}], "paging": {}}`)),
+			Request:    r,
+		}, nil
+	})
+
+	client, err := NewClient("test", Staging, WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.GetRoles(map[string]string{}); err != nil {
+		t.Fatal("could not get roles", err)
+	}
+
+	hc := &http.Client{Timeout: time.Second}
+	if WithHTTPClient(hc)(client); client.httpClient != hc {
+		t.Error("expected WithHTTPClient to set the http client")
+	}
+	if len(paths) != 1 || paths[0] != "/api/v1/roles" {
+		t.Errorf("expected the request to go through the transport, got %v", paths)
+	}
+}
+
+func TestBookLeave(t *testing.T) {
+	var sent []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/users/7/assignments" {
+			http.NotFound(w, r)
+			return
+		}
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		sent = append(sent, payload)
+		fmt.Fprint(w, `{"id": 1, "user_id": 7, "assignable_id": 3}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	a, _, err := client.BookLeave(7, 3, from, from.AddDate(0, 0, 1), 4)
+	if err != nil {
+		t.Fatal("could not book leave", err)
+	}
+	if a.ID != 1 {
+		t.Errorf("expected the created assignment to be decoded, got %+v", a)
+	}
+	if _, _, err := client.BookLeave(7, 3, from, from, 0); err != nil {
+		t.Fatal("could not book leave", err)
+	}
+
+	if len(sent) != 2 || sent[0]["allocation_mode"] != AllocationHoursPerDay || sent[0]["hours_per_day"] != 4.0 || sent[0]["assignable_id"] != 3.0 {
+		t.Fatalf("expected an hours per day leave, got %v", sent)
+	}
+	if sent[1]["allocation_mode"] != AllocationPercent || sent[1]["percent"] != 1.0 {
+		t.Errorf("expected a full day leave, got %v", sent[1])
+	}
+}
+
+func TestQueryEscaping(t *testing.T) {
+	opts := map[string]string{
+		"name":   "R&D Café",
+		"fields": "tags,assignments",
+		"q":      "a+b=c d/e?",
+	}
+
+	if q := queryfy(opts); q != "fields=tags%2Cassignments&name=R%26D+Caf%C3%A9&q=a%2Bb%3Dc+d%2Fe%3F" {
+		t.Errorf("unexpected query %v", q)
+	}
+
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = map[string]string{}
+		for k, v := range r.URL.Query() {
+			got[k] = v[0]
+		}
+		fmt.Fprint(w, `{"data": []}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	if _, _, err := client.GetProjects(opts); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, opts) {
+		t.Errorf("expected the server to receive %v, got %v", opts, got)
+	}
+}
+
+func TestUserLookups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/users/7":
+			fmt.Fprint(w, `{"id": 7, "email": "ada@example.com"}`)
+		case r.URL.Query().Get("page") == "2":
+			fmt.Fprint(w, `{"data": [{"id": 7, "email": "Ada@Example.com"}], "paging": {"page": 2, "next": null}}`)
+		default:
+			// The filter is ignored, the user is on the second page.
+			fmt.Fprint(w, `{"data": [{"id": 1, "email": "bob@example.com"}], "paging": {"page": 1, "next": "/users?page=2"}}`)
+		}
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	u, _, err := client.GetUserByID(7, map[string]string{})
+	if err != nil || u.ID != 7 {
+		t.Errorf("expected user 7, got %+v, %v", u, err)
+	}
+
+	u, _, err = client.FindUserByEmail("ada@example.com")
+	if err != nil || u.ID != 7 {
+		t.Errorf("expected user 7, got %+v, %v", u, err)
+	}
+
+	if _, _, err := client.FindUserByEmail("eve@example.com"); err != ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestProjectPhaseUpdates(t *testing.T) {
+	var calls, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		calls, bodies = append(calls, r.Method+" "+r.URL.Path), append(bodies, string(b))
+		fmt.Fprint(w, `{"id": 9, "parent_id": 42, "phase_name": "Design", "archived": true}`)
+	}))
+	defer srv.Close()
+	client := &Client{token: "test", env: srv.URL}
+
+	ph := NewPhase()
+	ph.ID, ph.ParentID, ph.PhaseName = 9, 42, "Design"
+	if _, err := client.UpdateProjectPhase(ph); err != nil {
+		t.Fatal("could not update phase", err)
+	}
+
+	if _, err := client.DeleteProjectPhase(ph); err != nil {
+		t.Fatal("could not delete phase", err)
+	}
+	if !ph.Archived || ph.PhaseName != "Design" {
+		t.Errorf("expected the archived phase to be decoded, got %+v", ph.basePhase)
+	}
+
+	expected := []string{"PUT /projects/42/phases/9", "PUT /projects/42/phases/9"}
+	if !reflect.DeepEqual(calls, expected) || bodies[1] != `{"archived":true}` {
+		t.Errorf("expected calls %v archiving the phase, got %v %v", expected, calls, bodies)
+	}
+}
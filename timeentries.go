@@ -0,0 +1,114 @@
+package tenkft
+
+import (
+	"context"
+	"net/http"
+)
+
+// timeEntryPayload holds the fields of a TimeEntry 10000ft accepts on writes.
+type timeEntryPayload struct {
+	AssignableID AssignableID `json:"assignable_id"`
+	Date         string       `json:"date"`
+	Hours        float64      `json:"hours"`
+	Task         string       `json:"task,omitempty"`
+	Notes        string       `json:"notes,omitempty"`
+	BillRateID   int          `json:"bill_rate_id,omitempty"`
+	IsSuggestion bool         `json:"is_suggestion,omitempty"`
+}
+
+func (te *TimeEntry) payload() *timeEntryPayload {
+	return &timeEntryPayload{
+		AssignableID: te.AssignableID,
+		Date:         te.Date,
+		Hours:        te.Hours,
+		Task:         te.Task,
+		Notes:        te.Notes,
+		BillRateID:   te.BillRateID,
+		IsSuggestion: te.IsSuggestion,
+	}
+}
+
+// GetUserTimeEntries abstraction to GET /users/<id>/time_entries
+func (c *Client) GetUserTimeEntries(u *User, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return c.GetUserTimeEntriesCtx(context.Background(), u, opts)
+}
+
+// GetUserTimeEntriesCtx is GetUserTimeEntries honouring ctx.
+func (c *Client) GetUserTimeEntriesCtx(ctx context.Context, u *User, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return userTimeEntriesEndpoint.List(ctx, c, opts, int(u.ID))
+}
+
+// GetAllUserTimeEntries returns all time entries of a user - automatically paginates and
+// returns accumulated time entries. resp and err correspond to the latest one in the loop.
+func (c *Client) GetAllUserTimeEntries(u *User, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return c.GetAllUserTimeEntriesCtx(context.Background(), u, opts)
+}
+
+// GetAllUserTimeEntriesCtx is GetAllUserTimeEntries honouring ctx, see Endpoint.ListAll
+// for what is returned when it is canceled.
+func (c *Client) GetAllUserTimeEntriesCtx(ctx context.Context, u *User, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return userTimeEntriesEndpoint.ListAll(ctx, c, opts, int(u.ID))
+}
+
+// GetProjectTimeEntries abstraction to GET /projects/<id>/time_entries
+func (c *Client) GetProjectTimeEntries(p *Project, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return c.GetProjectTimeEntriesCtx(context.Background(), p, opts)
+}
+
+// GetProjectTimeEntriesCtx is GetProjectTimeEntries honouring ctx.
+func (c *Client) GetProjectTimeEntriesCtx(ctx context.Context, p *Project, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return projectTimeEntriesEndpoint.List(ctx, c, opts, int(p.ID))
+}
+
+// GetAllProjectTimeEntries returns all time entries of a project - automatically
+// paginates and returns accumulated time entries. resp and err correspond to the latest
+// one in the loop.
+func (c *Client) GetAllProjectTimeEntries(p *Project, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return c.GetAllProjectTimeEntriesCtx(context.Background(), p, opts)
+}
+
+// GetAllProjectTimeEntriesCtx is GetAllProjectTimeEntries honouring ctx, see
+// Endpoint.ListAll for what is returned when it is canceled.
+func (c *Client) GetAllProjectTimeEntriesCtx(ctx context.Context, p *Project, opts map[string]string) (*TimeEntries, *http.Response, error) {
+	return projectTimeEntriesEndpoint.ListAll(ctx, c, opts, int(p.ID))
+}
+
+// GetTimeEntry abstraction to GET /users/<id>/time_entries/<id>
+func (c *Client) GetTimeEntry(userID UserID, id int, opts map[string]string) (*TimeEntry, *http.Response, error) {
+	return c.GetTimeEntryCtx(context.Background(), userID, id, opts)
+}
+
+// GetTimeEntryCtx is GetTimeEntry honouring ctx.
+func (c *Client) GetTimeEntryCtx(ctx context.Context, userID UserID, id int, opts map[string]string) (*TimeEntry, *http.Response, error) {
+	return userTimeEntriesEndpoint.Get(ctx, c, id, opts, int(userID))
+}
+
+// CreateTimeEntry abstraction to POST /users/<id>/time_entries for te.UserID
+func (c *Client) CreateTimeEntry(te *TimeEntry) (*http.Response, error) {
+	return c.CreateTimeEntryCtx(context.Background(), te)
+}
+
+// CreateTimeEntryCtx is CreateTimeEntry honouring ctx.
+func (c *Client) CreateTimeEntryCtx(ctx context.Context, te *TimeEntry) (*http.Response, error) {
+	return userTimeEntriesEndpoint.Create(ctx, c, te.payload(), te, int(te.UserID))
+}
+
+// UpdateTimeEntry abstraction to PUT /users/<id>/time_entries/<id>
+func (c *Client) UpdateTimeEntry(te *TimeEntry) (*http.Response, error) {
+	return c.UpdateTimeEntryCtx(context.Background(), te)
+}
+
+// UpdateTimeEntryCtx is UpdateTimeEntry honouring ctx.
+func (c *Client) UpdateTimeEntryCtx(ctx context.Context, te *TimeEntry) (*http.Response, error) {
+	return userTimeEntriesEndpoint.Update(ctx, c, te.ID, te.payload(), te, int(te.UserID))
+}
+
+// DeleteTimeEntry abstraction to DELETE /users/<id>/time_entries/<id>
+func (c *Client) DeleteTimeEntry(te *TimeEntry) (*http.Response, error) {
+	return c.DeleteTimeEntryCtx(context.Background(), te)
+}
+
+// DeleteTimeEntryCtx is DeleteTimeEntry honouring ctx.
+func (c *Client) DeleteTimeEntryCtx(ctx context.Context, te *TimeEntry) (*http.Response, error) {
+	return userTimeEntriesEndpoint.Delete(ctx, c, te.ID, int(te.UserID))
+}
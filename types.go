@@ -1,5 +1,15 @@
 package tenkft
 
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// Types embedding a pointer to an unexported base struct, e.g. Project and baseProject,
+// have an UnmarshalJSON allocating it before decoding: encoding/json cannot allocate
+// embedded pointers to unexported structs by itself.
+
 // Projects a collection of project - emulates /projects
 type Projects struct {
 	Data   []*Project `json:"data"`
@@ -7,7 +17,7 @@ type Projects struct {
 }
 
 // GetByID get a project from collection by id
-func (ps *Projects) GetByID(id int) (targetProject *Project) {
+func (ps *Projects) GetByID(id ProjectID) (targetProject *Project) {
 	for _, p := range ps.Data {
 		if p.ID == id {
 			targetProject = p
@@ -45,35 +55,52 @@ type baseProject struct {
 // Project abstraction to the /project schema
 type Project struct {
 	*baseProject
-	ID                  int         `json:"id"`
-	ArchivedAt          string      `json:"archived_at"`
-	GUID                string      `json:"guid"`
-	ParentID            int         `json:"parent_id"`
-	SecureURL           string      `json:"secureurl"`
-	SecureURLExpiration string      `json:"secureurl_expiration"`
-	Settings            interface{} `json:"settings"`
-	TimeentryLockout    interface{} `json:"timeentry_lockout"`
-	DeletedAt           string      `json:"deleted_at"`
-	CreatedAt           string      `json:"created_at"`
-	UpdatedAt           string      `json:"updated_at"`
-	UseParentBillRates  bool        `json:"use_parent_bill_rates"`
-	Thumbnail           string      `json:"thumbnail"`
-	Type                string      `json:"type"`
-	HasPendingUpdates   bool        `json:"has_pending_updates"`
-	Tags                Tags        `json:"tags"`
-	Assignments         Assignments `json:"assignments"`
-	BoundingStartdate   string      `json:"bounding_startdate"`
-	BoundingEnddate     string      `json:"bounding_enddate"`
-	ConfirmedHours      float64     `json:"confirmed_hours"`
-	ConfirmedDollars    float64     `json:"confirmed_dollars"`
-	ApprovedHours       float64     `json:"approved_hours"`
-	ApprovedDollars     float64     `json:"approved_dollars"`
-	UnconfirmedHours    float64     `json:"unconfirmed_hours"`
-	UnconfirmedDollars  float64     `json:"unconfirmed_dollars"`
-	ScheduledHours      float64     `json:"scheduled_hours"`
-	ScheduledDollars    float64     `json:"scheduled_dollars"`
-	FutureHours         float64     `json:"future_hours"`
-	FutureDollars       float64     `json:"future_dollars"`
+	ID                  ProjectID         `json:"id"`
+	ArchivedAt          string            `json:"archived_at"`
+	GUID                string            `json:"guid"`
+	ParentID            ProjectID         `json:"parent_id"`
+	OwnerID             UserID            `json:"owner_id"`
+	SecureURL           string            `json:"secureurl"`
+	SecureURLExpiration string            `json:"secureurl_expiration"`
+	Settings            Settings          `json:"settings"`
+	TimeentryLockout    interface{}       `json:"timeentry_lockout"`
+	DeletedAt           string            `json:"deleted_at"`
+	CreatedAt           string            `json:"created_at"`
+	UpdatedAt           string            `json:"updated_at"`
+	UseParentBillRates  bool              `json:"use_parent_bill_rates"`
+	Thumbnail           string            `json:"thumbnail"`
+	Type                string            `json:"type"`
+	HasPendingUpdates   bool              `json:"has_pending_updates"`
+	Tags                Tags              `json:"tags"`
+	Assignments         Assignments       `json:"assignments"`
+	CustomFieldValues   CustomFieldValues `json:"custom_field_values"`
+	BoundingStartdate   string            `json:"bounding_startdate"`
+	BoundingEnddate     string            `json:"bounding_enddate"`
+	ConfirmedHours      float64           `json:"confirmed_hours"`
+	ConfirmedDollars    float64           `json:"confirmed_dollars"`
+	ApprovedHours       float64           `json:"approved_hours"`
+	ApprovedDollars     float64           `json:"approved_dollars"`
+	UnconfirmedHours    float64           `json:"unconfirmed_hours"`
+	UnconfirmedDollars  float64           `json:"unconfirmed_dollars"`
+	ScheduledHours      float64           `json:"scheduled_hours"`
+	ScheduledDollars    float64           `json:"scheduled_dollars"`
+	FutureHours         float64           `json:"future_hours"`
+	FutureDollars       float64           `json:"future_dollars"`
+}
+
+// UnmarshalJSON allocates the embedded baseProject and keeps nested collections non-nil.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	if p.baseProject == nil {
+		p.baseProject = &baseProject{}
+	}
+
+	err := json.Unmarshal(data, (*project)(p))
+	nonNil(&p.Tags.Data, &p.Tags.Paging)
+	nonNil(&p.Assignments.Data, &p.Assignments.Paging)
+	nonNil(&p.CustomFieldValues.Data, &p.CustomFieldValues.Paging)
+
+	return err
 }
 
 type baseUser struct {
@@ -103,24 +130,75 @@ type User struct {
 	EmployeeNumber    interface{}    `json:"employee_number"`
 	GUID              string         `json:"guid"`
 	HasLogin          bool           `json:"has_login"`
-	ID                int            `json:"id"`
+	ID                UserID         `json:"id"`
 	InvitationPending bool           `json:"invitation_pending"`
 	LoginType         string         `json:"login_type"`
 	OfficePhone       string         `json:"office_phone"`
 	TerminationDate   string         `json:"termination_date"`
 	Thumbnail         string         `json:"thumbnail"`
 	Type              string         `json:"type"`
-	UserSettings      float64        `json:"user_settings"`
+	UserSettings      Settings       `json:"user_settings"`
 	UserTypeID        int            `json:"user_type_id"`
 	Tags              Tags           `json:"tags"`
 	Assignments       Assignments    `json:"assignments"`
 	Availabilities    Availabilities `json:"availabilities"`
+	// Directory holds the data Enrichers joined from outside 10000ft, e.g. department
+	// or manager. It is never sent to 10000ft.
+	Directory         map[string]string `json:"directory,omitempty"`
+	CustomFieldValues CustomFieldValues `json:"custom_field_values"`
+}
+
+// UnmarshalJSON allocates baseUser, keeps nested collections non-nil and accepts userAliases.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	if u.baseUser == nil {
+		u.baseUser = &baseUser{}
+	}
+
+	err := json.Unmarshal(normalize(data, userAliases), (*user)(u))
+	nonNil(&u.Tags.Data, &u.Tags.Paging)
+	nonNil(&u.Assignments.Data, &u.Assignments.Paging)
+	nonNil(&u.Availabilities.Data, &u.Availabilities.Paging)
+	nonNil(&u.CustomFieldValues.Data, &u.CustomFieldValues.Paging)
+
+	return err
+}
+
+// Clone returns a copy of u that can be modified without affecting u. Nested
+// collections such as Tags and Assignments are shared.
+func (u *User) Clone() *User {
+	clone := *u
+	clone.baseUser = &baseUser{}
+	if u.baseUser != nil {
+		*clone.baseUser = *u.baseUser
+	}
+	if u.Directory != nil {
+		clone.Directory = make(map[string]string, len(u.Directory))
+		for k, v := range u.Directory {
+			clone.Directory[k] = v
+		}
+	}
+
+	return &clone
 }
 
 // Tags holds a collection of tags - only reachable from a user or project.
 type Tags struct {
 	Data   []*Tag  `json:"data"`
 	Paging *Paging `json:"paging"`
+
+	names *nameIndex[*Tag]
+}
+
+// FindByName finds a *Tag by its value, nil if there is none.
+func (ts *Tags) FindByName(name string) *Tag {
+	return findByName(&ts.names, ts.Data, name, func(t *Tag) string { return t.Value })
+}
+
+// Reindex rebuilds the index FindByName uses, which is needed once items of Data are
+// replaced or renamed in place. Replacing or appending to Data is noticed on its own.
+func (ts *Tags) Reindex() {
+	reindex(&ts.names)
 }
 
 type baseTag struct {
@@ -133,6 +211,32 @@ type Tag struct {
 	ID int `json:"id"`
 }
 
+// UnmarshalJSON allocates the embedded baseTag before decoding.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type tag Tag
+	if t.baseTag == nil {
+		t.baseTag = &baseTag{}
+	}
+
+	return json.Unmarshal(data, (*tag)(t))
+}
+
+// CustomFieldValues holds a collection of custom field values - only reachable from a user or project.
+type CustomFieldValues struct {
+	Data   []*CustomFieldValue `json:"data"`
+	Paging *Paging             `json:"paging"`
+}
+
+// CustomFieldValue holds the value of a custom field for a user or a project.
+type CustomFieldValue struct {
+	ID              int         `json:"id"`
+	CustomFieldID   int         `json:"custom_field_id"`
+	CustomFieldName string      `json:"custom_field_name"`
+	Value           interface{} `json:"value"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+}
+
 // Tags holds a collection of tags - only reachable from a user or project.
 type Availabilities struct {
 	Data   []*Availability `json:"data"`
@@ -142,7 +246,7 @@ type Availabilities struct {
 // Tag holds a tag - only reachable from a user or a project.
 type Availability struct {
 	ID        int     `json:"id"`
-	UserID    int     `json:"user_id"`
+	UserID    UserID  `json:"user_id"`
 	StartsAt  string  `json:"starts_at"`
 	EndsAt    string  `json:"ends_at"`
 	Day0      float64 `json:"day0"`
@@ -181,47 +285,128 @@ type Paging struct {
 	Previous string `json:"previous"`
 	Self     string `json:"self"`
 	Next     string `json:"next"`
+	// TotalPages and TotalRecords are filled in once the last page has been fetched,
+	// e.g. by GetAll* methods, as list responses don't include them. 0 when unknown.
+	TotalPages   int `json:"total_pages,omitempty"`
+	TotalRecords int `json:"total_records,omitempty"`
 }
 
-// HasNext confirms whether there is a next pagination page.
+// HasNext confirms whether there is a next pagination page, false on a nil Paging.
 func (p *Paging) HasNext() bool {
-	return p.Next != "null" && p.Next != ""
+	return p != nil && p.Next != "null" && p.Next != ""
+}
+
+// HasPrevious confirms whether there is a previous pagination page, false on a nil Paging.
+func (p *Paging) HasPrevious() bool {
+	return p != nil && p.Previous != "null" && p.Previous != ""
 }
 
-// GetNextPage returns next page in pagination
+// complete fills in Page and PerPage from the Self URL when the response left them
+// out, and the totals when p is the last page, holding n items.
+func (p *Paging) complete(n int) {
+	if self, err := url.Parse(p.Self); err == nil && (p.Page == 0 || p.PerPage == 0) {
+		query := self.Query()
+		if p.Page == 0 {
+			p.Page, _ = strconv.Atoi(query.Get("page"))
+		}
+		if p.PerPage == 0 {
+			p.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+		}
+	}
+
+	if p.HasNext() || p.Page == 0 || (p.Page > 1 && p.PerPage == 0) {
+		return
+	}
+
+	p.TotalPages = p.Page
+	p.TotalRecords = (p.Page-1)*p.PerPage + n
+}
+
+// GetNextPage returns next page in pagination, 1 on a nil Paging. It is read from the
+// Next URL, falling back to the page after Page when Next doesn't hold a page number.
 func (p *Paging) GetNextPage() int {
+	if p == nil {
+		return 1
+	}
+
+	if params, ok := p.NextParams(); ok {
+		if page, err := strconv.Atoi(params["page"]); err == nil && page > 0 {
+			return page
+		}
+	}
+
 	return p.Page + 1
 }
 
+// NextParams returns the query parameters of the Next URL, e.g. page, per_page and the
+// filters of the request, false when there is no next page or Next can't be parsed.
+func (p *Paging) NextParams() (opts map[string]string, ok bool) {
+	if !p.HasNext() {
+		return
+	}
+
+	next, err := url.Parse(p.Next)
+	if err != nil {
+		return
+	}
+
+	return params(next.Query()), true
+}
+
 // Assignments abstraction to /assignments schema
 type Assignments struct {
 	Data   []*Assignment `json:"data"`
 	Paging *Paging       `json:"paging"`
 }
 
+// Allocation modes of an assignment, each going with one of FixedHours, HoursPerDay and
+// Percent. Use NewAssignmentFixedHours, NewAssignmentHoursPerDay and
+// NewAssignmentPercent to get a consistent payload.
+const (
+	AllocationFixed       = "fixed"
+	AllocationHoursPerDay = "hours_per_day"
+	AllocationPercent     = "percent"
+)
+
 type baseAssignment struct {
-	AllocationMode string  `json:"allocation_mode"`
-	AssignableID   int     `json:"assignable_id"`
-	EndsAt         string  `json:"ends_at"`
-	FixedHours     float64 `json:"fixed_hours,omitempty"`
-	HoursPerDay    float64 `json:"hours_per_day,omitempty"`
-	Percent        float64 `json:"percent,omitempty"`
-	StartsAt       string  `json:"starts_at"`
+	AllocationMode string       `json:"allocation_mode"`
+	AssignableID   AssignableID `json:"assignable_id"`
+	EndsAt         string       `json:"ends_at"`
+	FixedHours     float64      `json:"fixed_hours,omitempty"`
+	HoursPerDay    float64      `json:"hours_per_day,omitempty"`
+	Percent        float64      `json:"percent,omitempty"`
+	StartsAt       string       `json:"starts_at"`
 }
 
 // Assignment an abstraction to an assignment schema
 type Assignment struct {
 	*baseAssignment
-	AllDayAssignment  bool    `json:"all_day_assignment"`
-	BillRate          float64 `json:"bill_rate"`
-	BillRateID        int     `json:"bill_rate_id"`
-	CreatedAt         string  `json:"created_at"`
-	ID                int     `json:"id"`
-	RepetitionID      int     `json:"repetition_id"`
-	ResourceRequestID int     `json:"resource_request_id"`
-	Status            string  `json:"status"`
-	UpdatedAt         string  `json:"updated_at"`
-	UserID            int     `json:"user_id"`
+	AllDayAssignment  bool         `json:"all_day_assignment"`
+	BillRate          float64      `json:"bill_rate"`
+	BillRateID        int          `json:"bill_rate_id"`
+	CreatedAt         string       `json:"created_at"`
+	ID                AssignmentID `json:"id"`
+	RepetitionID      int          `json:"repetition_id"`
+	ResourceRequestID int          `json:"resource_request_id"`
+	Status            string       `json:"status"`
+	UpdatedAt         string       `json:"updated_at"`
+	UserID            UserID       `json:"user_id"`
+
+	// User, Project and LeaveType are only set when the assignment was expanded,
+	// see WithAssignmentExpansion.
+	User      *User      `json:"-"`
+	Project   *Project   `json:"-"`
+	LeaveType *LeaveType `json:"-"`
+}
+
+// UnmarshalJSON allocates the embedded baseAssignment before decoding.
+func (a *Assignment) UnmarshalJSON(data []byte) error {
+	type assignment Assignment
+	if a.baseAssignment == nil {
+		a.baseAssignment = &baseAssignment{}
+	}
+
+	return json.Unmarshal(data, (*assignment)(a))
 }
 
 // Phases abstraction to project phases schema
@@ -240,16 +425,16 @@ type basePhase struct {
 // Phase abstraction to a project phase object
 type Phase struct {
 	*basePhase
-	ID                  int         `json:"id"`
+	ID                  ProjectID   `json:"id"`
 	ArchivedAt          string      `json:"archived_at"`
 	Description         string      `json:"description"`
 	GUID                string      `json:"guid"`
 	Name                string      `json:"name"`
-	ParentID            int         `json:"parent_id"`
+	ParentID            ProjectID   `json:"parent_id"`
 	ProjectCode         string      `json:"project_code"`
 	SecureURL           string      `json:"secureurl"`
 	SecureURLExpiration string      `json:"secureurl_expiration"`
-	Settings            interface{} `json:"settings"`
+	Settings            Settings    `json:"settings"`
 	TimeentryLockout    interface{} `json:"timeentry_lockout"`
 	DeletedAt           string      `json:"deleted_at"`
 	CreatedAt           string      `json:"created_at"`
@@ -262,6 +447,16 @@ type Phase struct {
 	ProjectState        string      `json:"project_state"`
 }
 
+// UnmarshalJSON allocates the embedded basePhase before decoding.
+func (ph *Phase) UnmarshalJSON(data []byte) error {
+	type phase Phase
+	if ph.basePhase == nil {
+		ph.basePhase = &basePhase{}
+	}
+
+	return json.Unmarshal(data, (*phase)(ph))
+}
+
 // PlaceholderResources abstraction to /placeholder_resources
 type PlaceholderResources struct {
 	Data   []*PlaceholderResource `json:"data"`
@@ -286,39 +481,61 @@ type PlaceholderResource struct {
 	Color        string  `json:"color"`
 }
 
+// UnmarshalJSON accepts display_name and bill_rate for DisplayName and Billrate.
+func (pr *PlaceholderResource) UnmarshalJSON(data []byte) error {
+	type placeholderResource PlaceholderResource
+
+	return json.Unmarshal(normalize(data, placeholderAliases), (*placeholderResource)(pr))
+}
+
 // LeaveTypes abstraction to /leave_types response collection
 type LeaveTypes struct {
 	Data   []*LeaveType `json:"data"`
 	Paging *Paging      `json:"paging"`
+
+	names *nameIndex[*LeaveType]
 }
 
-// FindByName finds a *LeaveType by its name
-func (lts *LeaveTypes) FindByName(name string) (lt *LeaveType) {
-	for _, lt = range lts.Data {
-		if lt.Name == name {
-			return
-		}
-	}
+// FindByName finds a *LeaveType by its name, nil if there is none.
+func (lts *LeaveTypes) FindByName(name string) *LeaveType {
+	return findByName(&lts.names, lts.Data, name, func(lt *LeaveType) string { return lt.Name })
+}
 
-	return
+// Reindex rebuilds the index FindByName uses, which is needed once items of Data are
+// replaced or renamed in place. Replacing or appending to Data is noticed on its own.
+func (lts *LeaveTypes) Reindex() {
+	reindex(&lts.names)
 }
 
 // LeaveType abstraction to LeaveType object
 type LeaveType struct {
-	ID          int    `json:"id"`
-	Description string `json:"description"`
-	GUID        string `json:"guid"`
-	Name        string `json:"name"`
-	DeletedAt   string `json:"deleted_at"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
-	Type        string `json:"type"`
+	ID          LeaveTypeID `json:"id"`
+	Description string      `json:"description"`
+	GUID        string      `json:"guid"`
+	Name        string      `json:"name"`
+	DeletedAt   string      `json:"deleted_at"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
+	Type        string      `json:"type"`
 }
 
 // Roles abstraction to /roles schema
 type Roles struct {
 	Data   []*Role `json:"data"`
 	Paging *Paging `json:"paging"`
+
+	names *nameIndex[*Role]
+}
+
+// FindByName finds a *Role by its value, nil if there is none.
+func (rs *Roles) FindByName(name string) *Role {
+	return findByName(&rs.names, rs.Data, name, func(r *Role) string { return r.Value })
+}
+
+// Reindex rebuilds the index FindByName uses, which is needed once items of Data are
+// replaced or renamed in place. Replacing or appending to Data is noticed on its own.
+func (rs *Roles) Reindex() {
+	reindex(&rs.names)
 }
 
 // Role abstraction to a role object
@@ -335,40 +552,135 @@ type BillRates struct {
 
 // BillRate abstraction to a role object
 type BillRate struct {
-	ID           int     `json:"id"`
-	Rate         float64 `json:"rate"`
-	AssignableID int     `json:"assignable_id"`
-	DisciplineID int     `json:"discipline_id"`
-	RoleID       int     `json:"role_id"`
-	UserID       int     `json:"user_id"`
-	StartsAt     string  `json:"starts_at"`
-	EndsAt       string  `json:"ends_at"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
-	Startdate    string  `json:"startdate"`
-	Enddate      string  `json:"enddate"`
-}
-
+	ID           int          `json:"id"`
+	Rate         float64      `json:"rate"`
+	AssignableID AssignableID `json:"assignable_id"`
+	DisciplineID int          `json:"discipline_id"`
+	RoleID       int          `json:"role_id"`
+	UserID       UserID       `json:"user_id"`
+	StartsAt     string       `json:"starts_at"`
+	EndsAt       string       `json:"ends_at"`
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
+	Startdate    string       `json:"startdate"`
+	Enddate      string       `json:"enddate"`
+}
+
+// TimeEntries abstraction to /time_entries schema
 type TimeEntries struct {
 	Data   []*TimeEntry `json:"data"`
 	Paging *Paging      `json:"paging"`
 }
 
+// TimeEntry hours a user tracked against a project, phase or leave type on a date.
 type TimeEntry struct {
-	Task           string  `json:"task"`
-	ScheduledHours float64 `json:"scheduled_hours"`
-	Hours          float64 `json:"hours"`
-	BillRateID     int     `json:"bill_rate_id"`
-	AssignableID   int     `json:"assignable_id"`
-	UpdatedAt      string  `json:"updated_at"`
-	ID             int     `json:"id": 591986688`
-	BillRate       float64 `json:"bill_rate"`
-	Notes          string  `json:"notes"`
-	UserID         int     `json:"user_id"`
-	IsSuggestion   bool    `json:"is_suggestion"`
-	Date           string  `json:"date"`
-	CreatedAt      string  `json:"created_at"`
-	AssignableType string  `json:"assignable_type"`
+	Task           string       `json:"task"`
+	ScheduledHours float64      `json:"scheduled_hours"`
+	Hours          float64      `json:"hours"`
+	BillRateID     int          `json:"bill_rate_id"`
+	AssignableID   AssignableID `json:"assignable_id"`
+	UpdatedAt      string       `json:"updated_at"`
+	ID             int          `json:"id"`
+	BillRate       float64      `json:"bill_rate"`
+	Notes          string       `json:"notes"`
+	UserID         UserID       `json:"user_id"`
+	IsSuggestion   bool         `json:"is_suggestion"`
+	Date           string       `json:"date"`
+	CreatedAt      string       `json:"created_at"`
+	AssignableType string       `json:"assignable_type"`
+}
+
+// UnmarshalJSON accepts billrate for BillRate.
+func (te *TimeEntry) UnmarshalJSON(data []byte) error {
+	type timeEntry TimeEntry
+
+	return json.Unmarshal(normalize(data, timeEntryAliases), (*timeEntry)(te))
+}
+
+// BudgetItems abstraction to /projects/<id>/budget_items schema
+type BudgetItems struct {
+	Data   []*BudgetItem `json:"data"`
+	Paging *Paging       `json:"paging"`
+}
+
+// Budget item types, the ItemType of a BudgetItem.
+const (
+	BudgetTimeFees = "TimeFees"
+	BudgetExpenses = "Expenses"
+)
+
+type baseBudgetItem struct {
+	ItemType      string  `json:"item_type"`
+	Category      string  `json:"category,omitempty"`
+	Amount        float64 `json:"amount"`
+	PeritemAmount float64 `json:"peritem_amount,omitempty"`
+	PeritemLabel  string  `json:"peritem_label,omitempty"`
+}
+
+// BudgetItem abstraction to a project budget item, an amount of time and fees or
+// expenses budgeted for a project.
+type BudgetItem struct {
+	*baseBudgetItem
+	ID           int          `json:"id"`
+	AssignableID AssignableID `json:"assignable_id"`
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
+}
+
+// UnmarshalJSON allocates the embedded baseBudgetItem before decoding.
+func (bi *BudgetItem) UnmarshalJSON(data []byte) error {
+	type budgetItem BudgetItem
+	if bi.baseBudgetItem == nil {
+		bi.baseBudgetItem = &baseBudgetItem{}
+	}
+
+	return json.Unmarshal(data, (*budgetItem)(bi))
+}
+
+// ExpenseItems abstraction to /expense_items schema
+type ExpenseItems struct {
+	Data   []*ExpenseItem `json:"data"`
+	Paging *Paging        `json:"paging"`
+}
+
+type baseExpenseItem struct {
+	AssignableID AssignableID `json:"assignable_id"`
+	Date         string       `json:"date"`
+	Amount       float64      `json:"amount"`
+	Category     string       `json:"category,omitempty"`
+	Notes        string       `json:"notes,omitempty"`
+	IsSuggestion bool         `json:"is_suggestion,omitempty"`
+}
+
+// ExpenseItem an expense of a user on a project. Suggestions are scheduled expenses,
+// the others were incurred.
+type ExpenseItem struct {
+	*baseExpenseItem
+	ID             int    `json:"id"`
+	UserID         UserID `json:"user_id"`
+	AssignableType string `json:"assignable_type"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// UnmarshalJSON allocates the embedded baseExpenseItem before decoding.
+func (ei *ExpenseItem) UnmarshalJSON(data []byte) error {
+	type expenseItem ExpenseItem
+	if ei.baseExpenseItem == nil {
+		ei.baseExpenseItem = &baseExpenseItem{}
+	}
+
+	return json.Unmarshal(data, (*expenseItem)(ei))
+}
+
+// Scheduled reports whether ei is a planned expense rather than an incurred one.
+func (ei *ExpenseItem) Scheduled() bool {
+	return ei.IsSuggestion
+}
+
+// Incurred reports whether ei was actually spent.
+func (ei *ExpenseItem) Incurred() bool {
+	return !ei.IsSuggestion
 }
 
 type Holidays struct {
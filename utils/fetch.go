@@ -5,25 +5,59 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 )
 
-// Fetch optimized 10kft fetch helper
+// Backoffs before retrying a throttled request and one that failed otherwise.
+var (
+	throttleBackoff = 10 * time.Second
+	retryBackoff    = 2 * time.Second
+)
+
+// Fetch optimized 10kft fetch helper. Every attempt sends a new request built from
+// opts.Body or opts.RawBody, so retried writes carry their payload.
 func (opts FetchOpts) Fetch() (resp *http.Response, err error) {
-	c := &http.Client{}
-	payload := strings.NewReader(opts.Body)
+	if opts.Stats != nil {
+		opts.Stats.Attempts++
+		defer func() {
+			if resp != nil {
+				opts.Stats.StatusCode = resp.StatusCode
+			}
+		}()
+	}
+
+	c := opts.Client
+	if c == nil {
+		c = &http.Client{}
+	}
+
+	var payload io.Reader = strings.NewReader(opts.Body)
+	if opts.RawBody != nil {
+		payload = bytes.NewReader(opts.RawBody)
+	}
 
 	req, err := http.NewRequest(opts.Method, opts.URL, payload)
 	if err != nil {
 		return &http.Response{}, err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
+	if opts.Context != nil {
+		req = req.WithContext(opts.Context)
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Add("Content-Type", contentType)
 	for key, value := range opts.Headers {
 		req.Header.Add(key, value)
 	}
@@ -35,14 +69,23 @@ func (opts FetchOpts) Fetch() (resp *http.Response, err error) {
 
 	if resp.StatusCode == 429 && opts.MaxRetries > 0 {
 		opts.MaxRetries--
-		time.Sleep(time.Second * 10)
+		resp.Body.Close()
+		if err = opts.backoff(throttleBackoff); err != nil {
+			return
+		}
 		resp, err = opts.Fetch()
+		if err != nil {
+			return
+		}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		if opts.MaxRetries > 0 {
 			opts.MaxRetries--
-			time.Sleep(time.Second * 2)
+			resp.Body.Close()
+			if err = opts.backoff(retryBackoff); err != nil {
+				return
+			}
 			resp, err = opts.Fetch()
 		} else {
 			b, err := ioutil.ReadAll(resp.Body)
@@ -51,17 +94,77 @@ func (opts FetchOpts) Fetch() (resp *http.Response, err error) {
 				return resp, err
 			}
 
-			err = fmt.Errorf("Non OK status Code: %v, body: %v", resp.StatusCode, string(b))
-
 			resp.Body.Close()
 
-			return resp, err
+			return resp, &StatusError{StatusCode: resp.StatusCode, RequestID: RequestID(resp), Body: b}
 		}
 	}
 
 	return
 }
 
+// backoff sleeps for d before a retry, accounting for it in Stats.
+func (opts FetchOpts) backoff(d time.Duration) error {
+	if opts.Stats != nil {
+		opts.Stats.Backoff += d
+	}
+
+	return Sleep(opts.Context, d)
+}
+
+// Sleep waits for d, returning ctx.Err() early if ctx is canceled first. A nil ctx
+// never is.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StatusError is returned by Fetch when the response still has a non 2xx status once
+// the retries are exhausted. The response body is read and closed, Body holds it.
+type StatusError struct {
+	StatusCode int
+	RequestID  string
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("Non OK status Code: %v, request id: %v, body: %v", e.StatusCode, e.RequestID, string(e.Body))
+	}
+
+	return fmt.Sprintf("Non OK status Code: %v, body: %v", e.StatusCode, string(e.Body))
+}
+
+// requestIDHeaders are the headers the upstream request ID may be sent in, by priority.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id"}
+
+// RequestID returns the upstream request ID of resp, or "" if it carries none.
+func RequestID(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
+
 // NewFetchOpts opts
 func NewFetchOpts(url, method, body string, headers map[string]string, maxRetries int) (FetchOpts, error) {
 	var err error
@@ -93,4 +196,24 @@ type FetchOpts struct {
 	Body       string
 	Headers    map[string]string
 	MaxRetries int
+	// RawBody, when set, is sent instead of Body, e.g. an image or a multipart form.
+	RawBody []byte
+	// ContentType is the Content-Type of the body, "application/json" when empty.
+	ContentType string
+	// Client sends the requests, a new http.Client without timeout when nil.
+	Client *http.Client
+	// Context is attached to the request when set.
+	Context context.Context
+	// Stats, when set, is filled in with the retries Fetch went through.
+	Stats *RetryStats
+}
+
+// RetryStats describes the retries done for a single call.
+type RetryStats struct {
+	// Attempts is the number of requests sent, 1 when the call wasn't retried.
+	Attempts int
+	// Backoff is the total time spent waiting between attempts.
+	Backoff time.Duration
+	// StatusCode is the status of the last response, 0 if none was received.
+	StatusCode int
 }
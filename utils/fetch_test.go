@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchRetriesCarryBody(t *testing.T) {
+	throttle, retry := throttleBackoff, retryBackoff
+	t.Cleanup(func() { throttleBackoff, retryBackoff = throttle, retry })
+	throttleBackoff, retryBackoff = 0, 0
+
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+
+		switch len(bodies) {
+		case 1:
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "oops", http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	opts, err := NewFetchOpts(srv.URL, http.MethodPut, `{"name": "Engine"}`, map[string]string{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &RetryStats{}
+	opts.Stats = stats
+
+	resp, err := opts.Fetch()
+	if err != nil {
+		t.Fatal("expected the write to succeed after retries", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 3 || stats.Attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %v", len(bodies))
+	}
+	for i, b := range bodies {
+		if b != `{"name": "Engine"}` {
+			t.Errorf("expected attempt %v to carry the payload, got %q", i+1, b)
+		}
+	}
+}
+
+func TestFetchRawBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "image/png" || string(b) != "\x89PNG" {
+			http.Error(w, "unexpected body", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	opts, err := NewFetchOpts(srv.URL, http.MethodPost, "", map[string]string{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.RawBody, opts.ContentType = []byte("\x89PNG"), "image/png"
+
+	resp, err := opts.Fetch()
+	if err != nil {
+		t.Fatal("expected the raw body to be sent", err)
+	}
+	resp.Body.Close()
+}
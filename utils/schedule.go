@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Schedule runs a job periodically, see RunEvery.
+type Schedule struct {
+	Every time.Duration
+	// Jitter is the maximum random delay added before every run, so that jobs started
+	// together don't all hit the API at the same moment.
+	Jitter time.Duration
+	// OnError is called with the errors returned by the job, when set.
+	OnError func(error)
+	// OnSkip is called when a run is skipped because the previous one is still going.
+	OnSkip func()
+}
+
+// RunEvery runs job right away and then every d, with up to d/10 of jitter, until ctx
+// is canceled. A run is skipped while the previous one is still going, so jobs never
+// overlap. It returns ctx.Err() once the running job, if any, has returned, or an
+// error without running job when d isn't positive.
+func RunEvery(ctx context.Context, d time.Duration, job func(context.Context) error) error {
+	return Schedule{Every: d, Jitter: d / 10}.Run(ctx, job)
+}
+
+// Run runs job right away and then every s.Every until ctx is canceled, skipping runs
+// while the previous one is still going. It returns an error without running job when
+// s.Every isn't positive.
+func (s Schedule) Run(ctx context.Context, job func(context.Context) error) error {
+	if s.Every <= 0 {
+		return fmt.Errorf("schedule interval must be positive, got %v", s.Every)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		running bool
+	)
+
+	start := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if running {
+			if s.OnSkip != nil {
+				s.OnSkip()
+			}
+			return
+		}
+		running = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				running = false
+				mu.Unlock()
+			}()
+
+			if s.Jitter > 0 {
+				select {
+				case <-time.After(time.Duration(rand.Int63n(int64(s.Jitter)))):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if err := job(ctx); err != nil && s.OnError != nil {
+				s.OnError(err)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(s.Every)
+	defer ticker.Stop()
+
+	start()
+	for {
+		select {
+		case <-ticker.C:
+			start()
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduleSkipsOverlappingRuns(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	var runs, skips, concurrent, maxConcurrent int32
+	s := Schedule{
+		Every:  10 * time.Millisecond,
+		OnSkip: func() { atomic.AddInt32(&skips, 1) },
+	}
+
+	err := s.Run(ctx, func(ctx context.Context) error {
+		n := atomic.AddInt32(&concurrent, 1)
+		if n > atomic.LoadInt32(&maxConcurrent) {
+			atomic.StoreInt32(&maxConcurrent, n)
+		}
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(25 * time.Millisecond)
+		atomic.AddInt32(&concurrent, -1)
+		return nil
+	})
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline error, got %v", err)
+	}
+	if maxConcurrent != 1 {
+		t.Errorf("expected runs never to overlap, got %v at once", maxConcurrent)
+	}
+	if runs < 2 || skips == 0 {
+		t.Errorf("expected several runs and some skips, got %v runs and %v skips", runs, skips)
+	}
+}
+
+func TestScheduleRejectsNonPositiveInterval(t *testing.T) {
+	job := func(ctx context.Context) error {
+		t.Error("expected the job not to run")
+		return nil
+	}
+
+	for _, every := range []time.Duration{0, -time.Second} {
+		if err := (Schedule{Every: every}).Run(context.Background(), job); err == nil {
+			t.Errorf("expected an interval of %v to be rejected", every)
+		}
+	}
+	if err := RunEvery(context.Background(), 0, job); err == nil {
+		t.Error("expected RunEvery to reject a zero interval")
+	}
+}
@@ -0,0 +1,63 @@
+package tenkft
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIVersion identifies a version of the 10000ft API. It decides the path requests are
+// sent under and the behaviors the client adapts to, so that a new version of the API
+// is supported by adding one here rather than forking the package.
+type APIVersion string
+
+const (
+	// V1 is the API served under /api/v1 by every environment. It is used unless
+	// WithAPIVersion says otherwise.
+	V1 APIVersion = "v1"
+)
+
+// apiVersion holds what the client needs to know about an APIVersion.
+type apiVersion struct {
+	// prefix is the path of the API on the environment host.
+	prefix string
+	// maxPerPage is the largest page size served, larger per_page values are capped.
+	maxPerPage int
+}
+
+var apiVersions = map[APIVersion]apiVersion{
+	V1: {prefix: "/api/v1", maxPerPage: 1000},
+}
+
+// WithAPIVersion sets the version of the API the client talks to, V1 by default.
+func WithAPIVersion(v APIVersion) ClientOption {
+	return func(c *Client) {
+		c.version = v
+	}
+}
+
+// APIVersion returns the version of the API the client talks to.
+func (c *Client) APIVersion() APIVersion {
+	if c.version == "" {
+		return V1
+	}
+
+	return c.version
+}
+
+// api returns the details of the client's version.
+func (c *Client) api() apiVersion {
+	return apiVersions[c.APIVersion()]
+}
+
+// useVersion points the client's environment URL, one of the V1 environment
+// constants, to its version.
+func (c *Client) useVersion() error {
+	v, ok := apiVersions[c.APIVersion()]
+	if !ok {
+		return fmt.Errorf("unknown API version %v", c.version)
+	}
+
+	c.env = strings.TrimSuffix(c.env, apiVersions[V1].prefix) + v.prefix
+
+	return nil
+}
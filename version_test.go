@@ -0,0 +1,30 @@
+package tenkft
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	client, err := NewClient("", Production)
+	if err != nil || client.APIVersion() != V1 || client.Env() != Production {
+		t.Errorf("expected v1 on production by default, got %v on %v, %v", client.APIVersion(), client.Env(), err)
+	}
+
+	if _, err := NewClient("", Production, WithAPIVersion("v0")); err == nil {
+		t.Error("expected an unknown version to be rejected")
+	}
+
+	client = newTestClient(t, map[string]string{"/items": `{"data": [], "paging": {}}`})
+	items := Endpoint[[]*Role, *Role]{Path: "/items", PerPage: 5000, Wrap: func(data []*Role, paging *Paging) []*Role {
+		return data
+	}}
+	_, resp, err := items.ListAll(context.Background(), client, map[string]string{})
+	if err != nil {
+		t.Fatal("could not list items", err)
+	}
+	perPage := resp.Request.URL.Query().Get("per_page")
+	if perPage != "1000" {
+		t.Errorf("expected per_page to be capped to 1000, got %v", perPage)
+	}
+}